@@ -0,0 +1,48 @@
+package diagram
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nguyenta1993/service-kit/saga/saga"
+
+	"github.com/spf13/cobra"
+)
+
+// SagaDiagramCommand prints the diagram of the definition of every orchestrator
+func SagaDiagramCommand(orchestrators []*saga.Orchestrator, options ...saga.DescribeOption) *cobra.Command {
+	return &cobra.Command{
+		Use:       "saga-diagram [mermaid | dot | json]",
+		Short:     "print the diagrams of the sagas",
+		Long:      `saga-diagram prints the steps of every saga as a Mermaid flowchart (default), a Graphviz digraph or JSON`,
+		Args:      cobra.MaximumNArgs(1),
+		ValidArgs: []string{"mermaid", "dot", "json"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format := "mermaid"
+			if len(args) > 0 {
+				format = args[0]
+			}
+
+			for _, orchestrator := range orchestrators {
+				description := orchestrator.Describe(options...)
+
+				switch format {
+				case "mermaid":
+					fmt.Fprintln(cmd.OutOrStdout(), saga.RenderMermaid(description))
+				case "dot":
+					fmt.Fprintln(cmd.OutOrStdout(), saga.RenderDOT(description))
+				case "json":
+					data, err := json.MarshalIndent(description, "", "  ")
+					if err != nil {
+						return err
+					}
+					fmt.Fprintln(cmd.OutOrStdout(), string(data))
+				default:
+					return fmt.Errorf("unknown diagram format `%s`; use mermaid, dot or json", format)
+				}
+			}
+
+			return nil
+		},
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"os"
 
 	"github.com/nguyenta1993/service-kit/command/constants"
+	"github.com/nguyenta1993/service-kit/command/diagram"
 	"github.com/nguyenta1993/service-kit/command/migration"
 	"github.com/nguyenta1993/service-kit/command/start"
+	"github.com/nguyenta1993/service-kit/saga/saga"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -39,3 +41,7 @@ func WithStartCommand(startFunc func(), cfg interface{}, dbConfigKeys ...string)
 func WithMigrationCommand(dbConfigKeys ...string) *cobra.Command {
 	return migration.MigrationCommand(dbConfigKeys...)
 }
+
+func WithSagaDiagramCommand(orchestrators []*saga.Orchestrator, options ...saga.DescribeOption) *cobra.Command {
+	return diagram.SagaDiagramCommand(orchestrators, options...)
+}
@@ -0,0 +1,311 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/nguyenta1993/service-kit/http/request"
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/saga"
+)
+
+// InstanceView is the representation of a saga instance returned by the admin routes
+type InstanceView struct {
+	SagaName          string      `json:"sagaName"`
+	SagaID            string      `json:"sagaId"`
+	SagaDataName      string      `json:"sagaDataName"`
+	SagaData          interface{} `json:"sagaData,omitempty"`
+	CurrentStep       int         `json:"currentStep"`
+	EndState          bool        `json:"endState"`
+	Compensating      bool        `json:"compensating"`
+	Cancelled         bool        `json:"cancelled"`
+	CancelReason      string      `json:"cancelReason,omitempty"`
+	ParentSagaName    string      `json:"parentSagaName,omitempty"`
+	ParentSagaID      string      `json:"parentSagaId,omitempty"`
+	DefinitionVersion int         `json:"definitionVersion"`
+	Deadline          *time.Time  `json:"deadline,omitempty"`
+	StepDeadline      *time.Time  `json:"stepDeadline,omitempty"`
+	Version           int         `json:"version"`
+	ModifiedAt        time.Time   `json:"modifiedAt"`
+}
+
+// MaxAdminListLimit is the most instances the admin routes list at once
+const MaxAdminListLimit = 1000
+
+type adminErrorResponse struct {
+	StatusCode int    `json:"statusCode"`
+	Message    string `json:"message"`
+}
+
+type admin struct {
+	store         saga.InstanceStore
+	orchestrators map[string]*saga.Orchestrator
+	logger        logger.Logger
+}
+
+// AdminRoutes mounts the routes to list, inspect and act on the saga instances of the orchestrators
+func AdminRoutes(router gin.IRouter, store saga.InstanceStore, log logger.Logger, orchestrators ...*saga.Orchestrator) {
+	a := &admin{
+		store:         store,
+		orchestrators: make(map[string]*saga.Orchestrator, len(orchestrators)),
+		logger:        log,
+	}
+
+	for _, orchestrator := range orchestrators {
+		a.orchestrators[orchestrator.SagaName()] = orchestrator
+	}
+
+	router.GET("/sagas/:sagaName/versions", a.versions)
+
+	group := router.Group("/sagas/:sagaName/instances")
+	group.GET("", a.list)
+	group.GET("/:sagaID", a.get)
+	group.GET("/:sagaID/history", a.history)
+	group.GET("/:sagaID/children", a.children)
+	group.POST("/:sagaID/resend", a.action("resend", (*saga.Orchestrator).Resend))
+	group.POST("/:sagaID/compensate", a.action("compensate", (*saga.Orchestrator).Compensate))
+	group.POST("/:sagaID/complete", a.action("complete", (*saga.Orchestrator).Complete))
+	group.POST("/:sagaID/cancel", a.cancel)
+}
+
+func (a *admin) list(c *gin.Context) {
+	lister, ok := a.store.(saga.InstanceLister)
+	if !ok {
+		a.error(c, http.StatusNotImplemented, saga.ErrStoreNotSupported.Error())
+		return
+	}
+
+	query := saga.InstanceQuery{
+		SagaName: c.Param("sagaName"),
+		State:    saga.InstanceState(c.Query("state")),
+	}
+
+	switch query.State {
+	case "", saga.InstanceRunning, saga.InstanceCompensating, saga.InstanceEnded:
+	default:
+		a.error(c, http.StatusBadRequest, "state must be one of running, compensating or ended")
+		return
+	}
+
+	var err error
+
+	if olderThan := c.Query("olderThan"); olderThan != "" {
+		var age time.Duration
+		if age, err = time.ParseDuration(olderThan); err != nil {
+			a.error(c, http.StatusBadRequest, "olderThan must be a duration")
+			return
+		}
+		query.ModifiedBefore = time.Now().Add(-age)
+	}
+
+	if query.Limit, err = intQuery(c, "limit"); err != nil || query.Limit < 0 {
+		a.error(c, http.StatusBadRequest, "limit must be a positive number")
+		return
+	}
+	if query.Limit == 0 || query.Limit > MaxAdminListLimit {
+		query.Limit = MaxAdminListLimit
+	}
+
+	if query.Offset, err = intQuery(c, "offset"); err != nil || query.Offset < 0 {
+		a.error(c, http.StatusBadRequest, "offset must be a positive number")
+		return
+	}
+
+	instances, err := lister.List(c.Request.Context(), query)
+	if err != nil {
+		a.logger.Error("error listing saga instances", zap.String("SagaName", query.SagaName), zap.Error(err))
+		a.error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	views := make([]InstanceView, 0, len(instances))
+	for _, instance := range instances {
+		views = append(views, newInstanceView(instance, false))
+	}
+
+	c.JSON(http.StatusOK, views)
+}
+
+func (a *admin) get(c *gin.Context) {
+	instance, err := a.store.Find(c.Request.Context(), c.Param("sagaName"), c.Param("sagaID"))
+	if err != nil {
+		a.findError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newInstanceView(instance, true))
+}
+
+func (a *admin) history(c *gin.Context) {
+	orchestrator, exists := a.orchestrators[c.Param("sagaName")]
+	if !exists {
+		a.error(c, http.StatusNotFound, "saga is not administered")
+		return
+	}
+
+	records, err := orchestrator.Timeline(c.Request.Context(), c.Param("sagaID"))
+	switch {
+	case errors.Is(err, saga.ErrHistoryNotRecorded):
+		a.error(c, http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		a.logger.Error("error reading saga history", zap.String("SagaName", c.Param("sagaName")), zap.String("SagaID", c.Param("sagaID")), zap.Error(err))
+		a.error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if records == nil {
+		records = []saga.HistoryRecord{}
+	}
+
+	c.JSON(http.StatusOK, records)
+}
+
+func (a *admin) versions(c *gin.Context) {
+	orchestrator, exists := a.orchestrators[c.Param("sagaName")]
+	if !exists {
+		a.error(c, http.StatusNotFound, "saga is not administered")
+		return
+	}
+
+	statuses, err := orchestrator.Versions(c.Request.Context())
+	if err != nil {
+		a.logger.Error("error reporting saga definition versions", zap.String("SagaName", c.Param("sagaName")), zap.Error(err))
+		a.error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statuses)
+}
+
+func (a *admin) children(c *gin.Context) {
+	finder, ok := a.store.(saga.ChildFinder)
+	if !ok {
+		a.error(c, http.StatusNotImplemented, saga.ErrStoreNotSupported.Error())
+		return
+	}
+
+	instances, err := finder.FindChildren(c.Request.Context(), c.Param("sagaName"), c.Param("sagaID"))
+	if err != nil {
+		a.logger.Error("error finding child saga instances", zap.String("SagaName", c.Param("sagaName")), zap.String("SagaID", c.Param("sagaID")), zap.Error(err))
+		a.error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	views := make([]InstanceView, 0, len(instances))
+	for _, instance := range instances {
+		views = append(views, newInstanceView(instance, false))
+	}
+
+	c.JSON(http.StatusOK, views)
+}
+
+func (a *admin) cancel(c *gin.Context) {
+	reason := c.Query("reason")
+
+	a.action("cancel", func(orchestrator *saga.Orchestrator, ctx context.Context, sagaID string) error {
+		return orchestrator.Cancel(ctx, sagaID, reason)
+	})(c)
+}
+
+func (a *admin) action(name string, fn func(*saga.Orchestrator, context.Context, string) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sagaName, sagaID := c.Param("sagaName"), c.Param("sagaID")
+
+		logger := a.logger.With(
+			zap.String("Action", name),
+			zap.String("SagaName", sagaName),
+			zap.String("SagaID", sagaID),
+			zap.String("ClientIP", c.ClientIP()),
+		)
+		if user, ok := c.Get(request.UserContextKey); ok {
+			if userContext, ok := user.(request.UserContext); ok {
+				logger = logger.With(zap.Int64("UserID", userContext.Id))
+			}
+		}
+
+		orchestrator, exists := a.orchestrators[sagaName]
+		if !exists {
+			logger.Warn("saga admin action rejected; the saga is not administered")
+			a.error(c, http.StatusNotFound, "saga is not administered")
+			return
+		}
+
+		err := fn(orchestrator, c.Request.Context(), sagaID)
+		switch {
+		case err == nil:
+			logger.Info("saga admin action executed")
+			c.Status(http.StatusNoContent)
+		case errors.Is(err, saga.ErrSagaEnded), errors.Is(err, saga.ErrSagaCompensating), errors.Is(err, saga.ErrSagaPastPivot), errors.Is(err, saga.ErrSagaCancelled):
+			logger.Warn("saga admin action rejected", zap.Error(err))
+			a.error(c, http.StatusConflict, err.Error())
+		default:
+			logger.Error("saga admin action failed", zap.Error(err))
+			a.findError(c, err)
+		}
+	}
+}
+
+func (a *admin) findError(c *gin.Context, err error) {
+	if errors.Is(err, saga.ErrInstanceNotFound) {
+		a.error(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	a.error(c, http.StatusInternalServerError, err.Error())
+}
+
+func (a *admin) error(c *gin.Context, statusCode int, message string) {
+	c.AbortWithStatusJSON(statusCode, adminErrorResponse{
+		StatusCode: statusCode,
+		Message:    message,
+	})
+}
+
+func newInstanceView(instance *saga.Instance, withData bool) InstanceView {
+	view := InstanceView{
+		SagaName:          instance.SagaName(),
+		SagaID:            instance.SagaID(),
+		SagaDataName:      instance.SagaData().SagaDataName(),
+		CurrentStep:       instance.CurrentStep(),
+		EndState:          instance.EndState(),
+		Compensating:      instance.Compensating(),
+		Cancelled:         instance.ExecutionState().Cancelled,
+		CancelReason:      instance.ExecutionState().CancelReason,
+		ParentSagaName:    instance.ParentSagaName(),
+		ParentSagaID:      instance.ParentSagaID(),
+		DefinitionVersion: instance.DefinitionVersion(),
+		Version:           instance.Version(),
+		ModifiedAt:        instance.ModifiedAt(),
+	}
+
+	// the store decodes the saga data with the type registered in core
+	if withData {
+		view.SagaData = instance.SagaData()
+	}
+
+	if deadline := instance.Deadline(); !deadline.IsZero() {
+		view.Deadline = &deadline
+	}
+
+	if stepDeadline := instance.StepDeadline(); !stepDeadline.IsZero() {
+		view.StepDeadline = &stepDeadline
+	}
+
+	return view
+}
+
+func intQuery(c *gin.Context, key string) (int, error) {
+	value := c.Query(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(value)
+}
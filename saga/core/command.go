@@ -36,13 +36,28 @@ func DeserializeCommand(commandName string, data []byte) (Command, error) {
 // Register commands using any form desired "&MyCommand{}", "MyCommand{}", "(*MyCommand)(nil)"
 //
 // Commands must be registered after first registering a marshaller you wish to use
+//
+// Pass WithVersion and WithUpcaster options along to register the schema version of the types
 func RegisterCommands(commands ...Command) {
+	var names []string
+	var options []VersionOption
+
 	for _, command := range commands {
+		if option, ok := command.(VersionOption); ok {
+			options = append(options, option)
+			continue
+		}
+
+		var commandName string
 		if v := reflect.ValueOf(command); v.Kind() == reflect.Ptr && v.Pointer() == 0 {
-			commandName := reflect.Zero(reflect.TypeOf(command).Elem()).Interface().(Command).CommandName()
-			registerType(commandName, command)
+			commandName = reflect.Zero(reflect.TypeOf(command).Elem()).Interface().(Command).CommandName()
 		} else {
-			registerType(command.CommandName(), command)
+			commandName = command.CommandName()
 		}
+
+		registerType(commandName, command)
+		names = append(names, commandName)
 	}
+
+	registerVersions(names, options)
 }
@@ -36,13 +36,28 @@ func DeserializeEvent(eventName string, data []byte) (Event, error) {
 // Register events using any form desired "&MyEvent{}", "MyEvent{}", "(*MyEvent)(nil)"
 //
 // Events must be registered after first registering a marshaller you wish to use
+//
+// Pass WithVersion and WithUpcaster options along to register the schema version of the types
 func RegisterEvents(events ...Event) {
+	var names []string
+	var options []VersionOption
+
 	for _, event := range events {
+		if option, ok := event.(VersionOption); ok {
+			options = append(options, option)
+			continue
+		}
+
+		var eventName string
 		if v := reflect.ValueOf(event); v.Kind() == reflect.Ptr && v.Pointer() == 0 {
-			eventName := reflect.Zero(reflect.TypeOf(event).Elem()).Interface().(Event).EventName()
-			registerType(eventName, event)
+			eventName = reflect.Zero(reflect.TypeOf(event).Elem()).Interface().(Event).EventName()
 		} else {
-			registerType(event.EventName(), event)
+			eventName = event.EventName()
 		}
+
+		registerType(eventName, event)
+		names = append(names, eventName)
 	}
+
+	registerVersions(names, options)
 }
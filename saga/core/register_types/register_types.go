@@ -2,6 +2,7 @@ package registertypes
 
 import (
 	"github.com/nguyenta1993/service-kit/saga/msg"
+	"github.com/nguyenta1993/service-kit/saga/saga"
 )
 
 // RegisterTypes registers internal library types
@@ -11,4 +12,5 @@ import (
 // Users: There shouldn't be any reason to call this directly.
 func RegisterTypes() {
 	msg.RegisterTypes()
+	saga.RegisterTypes()
 }
@@ -107,9 +107,11 @@ func marshal(typeName string, v interface{}) ([]byte, error) {
 	return marshaller.Marshal(v)
 }
 
-func unmarshal(typeName string, data []byte) (interface{}, error) {
+func unmarshal(versionedName string, data []byte) (interface{}, error) {
 	var t reflect.Type
 
+	typeName, version := parseVersionedName(versionedName)
+
 	marshaller := registry.defaultMarshaller
 
 	if marshaller != nil {
@@ -129,9 +131,14 @@ func unmarshal(typeName string, data []byte) (interface{}, error) {
 		return nil, fmt.Errorf("`%s` was not registered with any marshaller", typeName)
 	}
 
+	data, err := upcast(marshaller, typeName, version, data)
+	if err != nil {
+		return nil, err
+	}
+
 	dst := reflect.New(t).Interface()
 
-	err := marshaller.Unmarshal(data, dst)
+	err = marshaller.Unmarshal(data, dst)
 	return dst, err
 }
 
@@ -161,3 +168,18 @@ func registerMarshaller(marshaller Marshaller, affinityFn func(interface{}) bool
 
 	registry.marshallers = append(registry.marshallers, rm)
 }
+
+// IsRegistered returns whether or not a type has been registered with any marshaller under the type name
+func IsRegistered(typeName string) bool {
+	if registry.defaultMarshaller != nil && registry.defaultMarshaller.GetType(typeName) != nil {
+		return true
+	}
+
+	for _, s := range registry.marshallers {
+		if s.marshaller.GetType(typeName) != nil {
+			return true
+		}
+	}
+
+	return false
+}
@@ -36,13 +36,28 @@ func DeserializeReply(replyName string, data []byte) (Reply, error) {
 // Register replies using any form desired "&MyReply{}", "MyReply{}", "(*MyReply)(nil)"
 //
 // Replies must be registered after first registering a marshaller you wish to use
+//
+// Pass WithVersion and WithUpcaster options along to register the schema version of the types
 func RegisterReplies(replies ...Reply) {
+	var names []string
+	var options []VersionOption
+
 	for _, reply := range replies {
+		if option, ok := reply.(VersionOption); ok {
+			options = append(options, option)
+			continue
+		}
+
+		var replyName string
 		if v := reflect.ValueOf(reply); v.Kind() == reflect.Ptr && v.Pointer() == 0 {
-			replyName := reflect.Zero(reflect.TypeOf(reply).Elem()).Interface().(Reply).ReplyName()
-			registerType(replyName, reply)
+			replyName = reflect.Zero(reflect.TypeOf(reply).Elem()).Interface().(Reply).ReplyName()
 		} else {
-			registerType(reply.ReplyName(), reply)
+			replyName = reply.ReplyName()
 		}
+
+		registerType(replyName, reply)
+		names = append(names, replyName)
 	}
+
+	registerVersions(names, options)
 }
@@ -36,13 +36,28 @@ func DeserializeSagaData(sagaDataName string, data []byte) (SagaData, error) {
 // Register saga data using any form desired "&MySagaData{}", "MySagaData{}", "(*MySagaData)(nil)"
 //
 // SagaData must be registered after first registering a marshaller you wish to use
+//
+// Pass WithVersion and WithUpcaster options along to register the schema version of the types
 func RegisterSagaData(sagaDatas ...SagaData) {
+	var names []string
+	var options []VersionOption
+
 	for _, sagaData := range sagaDatas {
+		if option, ok := sagaData.(VersionOption); ok {
+			options = append(options, option)
+			continue
+		}
+
+		var sagaDataName string
 		if v := reflect.ValueOf(sagaData); v.Kind() == reflect.Ptr && v.Pointer() == 0 {
-			sagaDataName := reflect.Zero(reflect.TypeOf(sagaData).Elem()).Interface().(SagaData).SagaDataName()
-			registerType(sagaDataName, sagaData)
+			sagaDataName = reflect.Zero(reflect.TypeOf(sagaData).Elem()).Interface().(SagaData).SagaDataName()
 		} else {
-			registerType(sagaData.SagaDataName(), sagaData)
+			sagaDataName = sagaData.SagaDataName()
 		}
+
+		registerType(sagaDataName, sagaData)
+		names = append(names, sagaDataName)
 	}
+
+	registerVersions(names, options)
 }
@@ -0,0 +1,176 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Upcaster upgrades the marshalled fields of a type from one version to the next
+type Upcaster func(fields map[string]interface{}) (map[string]interface{}, error)
+
+// VersionOption configures the schema version of the types it is registered with
+type VersionOption func(*typeVersion)
+
+// SagaDataName lets the option be passed to RegisterSagaData
+func (VersionOption) SagaDataName() string { return "" }
+
+// EventName lets the option be passed to RegisterEvents
+func (VersionOption) EventName() string { return "" }
+
+// CommandName lets the option be passed to RegisterCommands
+func (VersionOption) CommandName() string { return "" }
+
+// ReplyName lets the option be passed to RegisterReplies
+func (VersionOption) ReplyName() string { return "" }
+
+type typeVersion struct {
+	version   int
+	upcasters map[int]Upcaster
+}
+
+var versions = struct {
+	types map[string]typeVersion
+	mu    sync.RWMutex
+}{
+	types: map[string]typeVersion{},
+}
+
+// WithVersion sets the current schema version of the type; types are at version 1 until registered otherwise
+func WithVersion(version int) VersionOption {
+	return func(v *typeVersion) {
+		v.version = version
+	}
+}
+
+// WithUpcaster sets the upcaster from a version to the next one; versions without one are passed on unchanged
+func WithUpcaster(from int, upcaster Upcaster) VersionOption {
+	return func(v *typeVersion) {
+		v.upcasters[from] = upcaster
+	}
+}
+
+// TypeVersion returns the current schema version of the type name
+func TypeVersion(typeName string) int {
+	versions.mu.RLock()
+	defer versions.mu.RUnlock()
+
+	if v, exists := versions.types[typeName]; exists {
+		return v.version
+	}
+
+	return 1
+}
+
+// VersionedName returns the type name with the version appended as "name@version"; version 1 is the bare type name
+func VersionedName(typeName string, version int) string {
+	if version <= 1 {
+		return typeName
+	}
+
+	return typeName + "@" + strconv.Itoa(version)
+}
+
+// VersionedSagaDataName returns the saga data name with its current version; stores save this name with the data
+func VersionedSagaDataName(sagaData SagaData) string {
+	return VersionedName(sagaData.SagaDataName(), TypeVersion(sagaData.SagaDataName()))
+}
+
+// registerVersions applies the options given to a register function to every type registered with them
+func registerVersions(typeNames []string, options []VersionOption) {
+	if len(options) == 0 {
+		return
+	}
+
+	for _, typeName := range typeNames {
+		registerVersion(typeName, options)
+	}
+}
+
+func registerVersion(typeName string, options []VersionOption) {
+	v := typeVersion{
+		version:   1,
+		upcasters: map[int]Upcaster{},
+	}
+
+	for _, option := range options {
+		option(&v)
+	}
+
+	if v.version < 1 {
+		panic(fmt.Sprintf("`%s` cannot be registered with version %d", typeName, v.version))
+	}
+
+	for from := range v.upcasters {
+		if from < 1 || from >= v.version {
+			panic(fmt.Sprintf("`%s` has an upcaster from version %d; the current version is %d", typeName, from, v.version))
+		}
+	}
+
+	versions.mu.Lock()
+	defer versions.mu.Unlock()
+
+	versions.types[typeName] = v
+}
+
+func parseVersionedName(name string) (string, int) {
+	i := strings.LastIndex(name, "@")
+	if i == -1 {
+		return name, 1
+	}
+
+	version, err := strconv.Atoi(name[i+1:])
+	if err != nil || version < 1 {
+		return name, 1
+	}
+
+	return name[:i], version
+}
+
+// upcast applies the upcasters between the version of the data and the current version of the type
+func upcast(marshaller Marshaller, typeName string, version int, data []byte) ([]byte, error) {
+	versions.mu.RLock()
+	v, exists := versions.types[typeName]
+	versions.mu.RUnlock()
+
+	current := 1
+	if exists {
+		current = v.version
+	}
+
+	switch {
+	case version == current:
+		return data, nil
+	case version > current:
+		return nil, fmt.Errorf("`%s` version %d is newer than the registered version %d", typeName, version, current)
+	}
+
+	fields := map[string]interface{}{}
+	if err := marshaller.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("`%s` version %d could not be unmarshalled for upcasting: %w", typeName, version, err)
+	}
+
+	for from := version; from < current; from++ {
+		upcaster, exists := v.upcasters[from]
+		if !exists {
+			continue
+		}
+
+		var err error
+		if fields, err = upcaster(fields); err != nil {
+			return nil, fmt.Errorf("`%s` could not be upcast from version %d: %w", typeName, from, err)
+		}
+	}
+
+	return marshaller.Marshal(fields)
+}
+
+func zeroValue(v interface{}) interface{} {
+	if value := reflect.ValueOf(v); value.Kind() == reflect.Ptr && value.Pointer() == 0 {
+		return reflect.Zero(reflect.TypeOf(v).Elem()).Interface()
+	}
+
+	return v
+}
@@ -0,0 +1,128 @@
+package core_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nguyenta1993/service-kit/saga/core"
+	_ "github.com/nguyenta1993/service-kit/saga/msgpack"
+
+	"github.com/shamaton/msgpack"
+)
+
+type customerDataV1 struct {
+	Name string
+}
+
+type customerData struct {
+	FirstName string
+	LastName  string
+	Country   string
+}
+
+func (customerData) SagaDataName() string { return "versionTest.customerData" }
+
+func TestDeserializeSagaDataVersions(t *testing.T) {
+	core.RegisterSagaData(customerData{},
+		core.WithVersion(3),
+		core.WithUpcaster(1, func(fields map[string]interface{}) (map[string]interface{}, error) {
+			name, _ := fields["Name"].(string)
+			parts := strings.SplitN(name, " ", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("name `%s` cannot be split", name)
+			}
+			return map[string]interface{}{"FirstName": parts[0], "LastName": parts[1]}, nil
+		}),
+		core.WithUpcaster(2, func(fields map[string]interface{}) (map[string]interface{}, error) {
+			fields["Country"] = "VN"
+			return fields, nil
+		}),
+	)
+
+	current, err := core.SerializeSagaData(customerData{FirstName: "Thi", LastName: "Nguyen", Country: "SG"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		name    string
+		data    interface{}
+		want    core.SagaData
+		wantErr bool
+	}{
+		"Version1": {
+			name: "versionTest.customerData",
+			data: customerDataV1{Name: "Thi Nguyen"},
+			want: &customerData{FirstName: "Thi", LastName: "Nguyen", Country: "VN"},
+		},
+		"Version2": {
+			name: "versionTest.customerData@2",
+			data: map[string]interface{}{"FirstName": "Thi", "LastName": "Nguyen"},
+			want: &customerData{FirstName: "Thi", LastName: "Nguyen", Country: "VN"},
+		},
+		"CurrentVersion": {
+			name: core.VersionedSagaDataName(customerData{}),
+			data: current,
+			want: &customerData{FirstName: "Thi", LastName: "Nguyen", Country: "SG"},
+		},
+		"NewerVersion": {
+			name:    "versionTest.customerData@4",
+			data:    current,
+			wantErr: true,
+		},
+		"UpcasterError": {
+			name:    "versionTest.customerData",
+			data:    customerDataV1{Name: "Thi"},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, ok := tt.data.([]byte)
+			if !ok {
+				if data, err = msgpack.Marshal(tt.data); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := core.DeserializeSagaData(tt.name, data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DeserializeSagaData() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) && !tt.wantErr {
+				t.Errorf("DeserializeSagaData() got = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+type orderEvent struct{}
+
+func (orderEvent) EventName() string { return "versionTest.orderEvent" }
+
+type orderReply struct{}
+
+func (orderReply) ReplyName() string { return "versionTest.orderReply" }
+
+func TestRegisterVersionOptions(t *testing.T) {
+	core.RegisterEvents(orderEvent{}, core.WithVersion(2))
+	core.RegisterReplies(orderReply{})
+
+	tests := map[string]struct {
+		typeName string
+		want     int
+	}{
+		"WithVersion":    {typeName: orderEvent{}.EventName(), want: 2},
+		"WithoutVersion": {typeName: orderReply{}.ReplyName(), want: 1},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := core.TypeVersion(tt.typeName); got != tt.want {
+				t.Errorf("TypeVersion() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -22,16 +22,30 @@ type SagaService struct {
 	Logger            logger.Logger
 	PgConn            pgx.Client
 	SagaInstanceStore saga.InstanceStore
-	Publisher         *msg.Publisher
-	Subscriber        *msg.Subscriber
+	// HistoryStore is nil for stores without one
+	HistoryStore saga.HistoryStore
+	// Session is given to the orchestrators with saga.WithOrchestratorSession; it is nil for stores without one
+	Session    saga.Session
+	Publisher  *msg.Publisher
+	Subscriber *msg.Subscriber
 }
 
-// Return the saga service and waiter function
-func NewSagaStore(ctx context.Context, log logger.Logger, producer msg.Producer, consumer msg.Consumer, pgConnStr string, waitFuncs ...func(ctx context.Context) error) (*SagaService, func(context.Context) error, func()) {
+// NewSagaStore returns the saga service using the store and the receiver middlewares of the store, and the waiter function
+func NewSagaStore(ctx context.Context, log logger.Logger, producer msg.Producer, consumer msg.Consumer, store saga.InstanceStore, middlewares ...func(msg.MessageReceiver) msg.MessageReceiver) (*SagaService, func(context.Context) error) {
 	s := &SagaService{
-		Logger: log,
+		Logger:            log,
+		SagaInstanceStore: store,
 	}
 
+	s.Subscriber = msg.NewSubscriber(consumer, log)
+	s.Subscriber.Use(append([]func(msg.MessageReceiver) msg.MessageReceiver{MessageInstrumentation()}, middlewares...)...)
+	s.Publisher = msg.NewPublisher(producer, log)
+
+	return s, s.waitForMessaging
+}
+
+// NewPostgresSagaStore returns the saga service using a pgx.SagaInstanceStore, the waiter function and a function closing the connection
+func NewPostgresSagaStore(ctx context.Context, log logger.Logger, producer msg.Producer, consumer msg.Consumer, pgConnStr string) (*SagaService, func(context.Context) error, func()) {
 	var pgConn *pgxpool.Pool
 	pgConn, err := pgxpool.Connect(ctx, pgConnStr)
 	if err != nil {
@@ -39,23 +53,26 @@ func NewSagaStore(ctx context.Context, log logger.Logger, producer msg.Producer,
 	}
 
 	// 1. Outbox: Use session client which will fetch a transaction from the context
-	s.PgConn = pgx.NewSessionClient()
+	sessionClient := pgx.NewSessionClient(pgx.WithSessionClientReader(pgConn))
+	// the stores keep using the pool outside of transactions, as they did before sessions were added
+	storeClient := pgx.NewSessionClient(pgx.WithSessionClientPool(pgConn))
 
-	s.SagaInstanceStore = pgx.NewSagaInstanceStore(log, pgConn)
-	s.Subscriber = msg.NewSubscriber(consumer, log)
-	s.Subscriber.Use(
-		MessageInstrumentation(),
+	s, waitFunc := NewSagaStore(ctx, log, producer, consumer,
+		pgx.NewSagaInstanceStore(log, storeClient),
 		// 3. Outbox: Use a message receiver middleware to start a new transaction for each incoming message
-		pgx.ReceiverSessionMiddleware(pgConn, s.Logger),
+		pgx.ReceiverSessionMiddleware(pgConn, log),
 	)
-	s.Publisher = msg.NewPublisher(producer, log)
+
+	s.PgConn = sessionClient
+	s.HistoryStore = pgx.NewSagaHistoryStore(log, storeClient)
+	s.Session = pgx.NewSession(pgConn, log)
 
 	closeFunc := func() {
 		if pgConn != nil {
 			pgConn.Close()
 		}
 	}
-	return s, s.waitForMessaging, closeFunc
+	return s, waitFunc, closeFunc
 }
 
 func MessageInstrumentation() func(msg.MessageReceiver) msg.MessageReceiver {
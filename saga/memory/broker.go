@@ -0,0 +1,162 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+)
+
+// Broker is an in-process message broker for tests and local development
+type Broker struct {
+	mu              sync.Mutex
+	logs            map[string][]msg.Message
+	cursors         map[string]map[string]*cursor
+	updated         chan struct{}
+	redeliveryDelay time.Duration
+	maxDeliveries   int
+	logger          logger.Logger
+}
+
+type cursor struct {
+	token  chan struct{}
+	offset int
+}
+
+// NewBroker constructs a new Broker
+func NewBroker(logger logger.Logger, options ...BrokerOption) *Broker {
+	b := &Broker{
+		logs:            map[string][]msg.Message{},
+		cursors:         map[string]map[string]*cursor{},
+		updated:         make(chan struct{}),
+		redeliveryDelay: DefaultRedeliveryDelay,
+		maxDeliveries:   DefaultMaxDeliveries,
+		logger:          logger,
+	}
+
+	for _, option := range options {
+		option(b)
+	}
+
+	return b
+}
+
+// Producer returns a msg.Producer that sends messages to the broker
+func (b *Broker) Producer() msg.Producer {
+	return &producer{broker: b}
+}
+
+// Consumer returns a msg.Consumer that listens to the broker as a member of the consumer group
+func (b *Broker) Consumer(group string) msg.Consumer {
+	return &consumer{
+		broker: b,
+		group:  group,
+		closed: make(chan struct{}),
+	}
+}
+
+// Published returns the messages sent to the channel in the order they were sent
+func (b *Broker) Published(channel string) []msg.Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]msg.Message{}, b.logs[channel]...)
+}
+
+// WaitFor returns the first message sent to the channel that matches the predicate, waiting for it to be sent
+func (b *Broker) WaitFor(ctx context.Context, channel string, predicate func(msg.Message) bool) (msg.Message, error) {
+	checked := 0
+
+	for {
+		b.mu.Lock()
+		messages, updated := b.logs[channel], b.updated
+		b.mu.Unlock()
+
+		for ; checked < len(messages); checked++ {
+			if predicate == nil || predicate(messages[checked]) {
+				return messages[checked], nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-updated:
+		}
+	}
+}
+
+func (b *Broker) send(channel string, message msg.Message) {
+	// copy the message so later changes to the headers are not seen by receivers
+	message = msg.NewMessage(message.Payload(), msg.WithMessageID(message.ID()), msg.WithHeaders(message.Headers()))
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.logs[channel] = append(b.logs[channel], message)
+
+	close(b.updated)
+	b.updated = make(chan struct{})
+}
+
+func (b *Broker) cursor(group, channel string) *cursor {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, exists := b.cursors[group]; !exists {
+		b.cursors[group] = map[string]*cursor{}
+	}
+
+	c, exists := b.cursors[group][channel]
+	if !exists {
+		c = &cursor{token: make(chan struct{}, 1)}
+		b.cursors[group][channel] = c
+	}
+
+	return c
+}
+
+// next returns the message at the offset, or a channel that is closed when another message is sent
+func (b *Broker) next(channel string, offset int) (msg.Message, <-chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if offset < len(b.logs[channel]) {
+		return b.logs[channel][offset], nil
+	}
+
+	return nil, b.updated
+}
+
+// deliver calls fn until the message is received; false is returned when the context is done first
+func (b *Broker) deliver(ctx context.Context, channel, group string, message msg.Message, fn msg.ReceiveMessageFunc) bool {
+	logger := b.logger.With(
+		zap.String("Channel", channel),
+		zap.String("Group", group),
+		zap.String("MessageID", message.ID()),
+	)
+
+	for deliveries := 1; ; deliveries++ {
+		err := fn(ctx, message)
+		if err == nil {
+			return true
+		}
+
+		if b.maxDeliveries != 0 && deliveries >= b.maxDeliveries {
+			logger.Error("message was not received; giving up", zap.Int("Deliveries", deliveries), zap.Error(err))
+			return true
+		}
+
+		logger.Warn("message was not received; redelivering", zap.Int("Deliveries", deliveries), zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(b.redeliveryDelay):
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package memory
+
+import (
+	"time"
+)
+
+// BrokerOption options for Broker
+type BrokerOption func(b *Broker)
+
+// WithBrokerRedeliveryDelay sets how long the broker waits before redelivering a message that was not received
+func WithBrokerRedeliveryDelay(delay time.Duration) BrokerOption {
+	return func(b *Broker) {
+		b.redeliveryDelay = delay
+	}
+}
+
+// WithBrokerMaxDeliveries sets how many times a message is delivered to a group before it is skipped; zero is unlimited
+func WithBrokerMaxDeliveries(maxDeliveries int) BrokerOption {
+	return func(b *Broker) {
+		b.maxDeliveries = maxDeliveries
+	}
+}
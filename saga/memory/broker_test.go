@@ -0,0 +1,155 @@
+package memory_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/memory"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+)
+
+func TestBroker_Delivery(t *testing.T) {
+	tests := map[string]struct {
+		groups        []string
+		failures      int
+		maxDeliveries int
+		messages      int
+		want          map[string]int
+	}{
+		"SingleGroup": {
+			groups:   []string{"group-a"},
+			messages: 3,
+			want:     map[string]int{"group-a": 3},
+		},
+		"FanOut": {
+			groups:   []string{"group-a", "group-b"},
+			messages: 3,
+			want:     map[string]int{"group-a": 3, "group-b": 3},
+		},
+		"CompetingListeners": {
+			groups:   []string{"group-a", "group-a"},
+			messages: 4,
+			want:     map[string]int{"group-a": 4},
+		},
+		"Redelivery": {
+			groups:        []string{"group-a"},
+			failures:      2,
+			maxDeliveries: 5,
+			messages:      1,
+			want:          map[string]int{"group-a": 3},
+		},
+		"GivesUp": {
+			groups:        []string{"group-a"},
+			failures:      10,
+			maxDeliveries: 2,
+			messages:      2,
+			want:          map[string]int{"group-a": 4},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			broker := memory.NewBroker(logger.NewDefaultLogger("error"),
+				memory.WithBrokerRedeliveryDelay(time.Millisecond),
+				memory.WithBrokerMaxDeliveries(tt.maxDeliveries),
+			)
+
+			var mu sync.Mutex
+			received := map[string]int{}
+			failures := tt.failures
+
+			wantTotal := 0
+			for _, count := range tt.want {
+				wantTotal += count
+			}
+			done := make(chan struct{})
+
+			var wg sync.WaitGroup
+			for _, g := range tt.groups {
+				group := g
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_ = broker.Consumer(group).Listen(ctx, "channel", func(ctx context.Context, message msg.Message) error {
+						mu.Lock()
+						defer mu.Unlock()
+
+						received[group]++
+						if wantTotal--; wantTotal == 0 {
+							close(done)
+						}
+
+						if failures > 0 {
+							failures--
+							return fmt.Errorf("receiver-error")
+						}
+
+						return nil
+					})
+				}()
+			}
+
+			for i := 0; i < tt.messages; i++ {
+				if err := broker.Producer().Send(ctx, "channel", msg.NewMessage([]byte(`{}`))); err != nil {
+					t.Fatalf("Send() error = %v", err)
+				}
+			}
+
+			select {
+			case <-done:
+			case <-ctx.Done():
+				t.Fatalf("timed out waiting for deliveries")
+			}
+
+			// give any unexpected extra deliveries a chance to happen
+			time.Sleep(20 * time.Millisecond)
+			cancel()
+			wg.Wait()
+
+			for group, want := range tt.want {
+				if received[group] != want {
+					t.Errorf("deliveries to %s = %d, want %d", group, received[group], want)
+				}
+			}
+		})
+	}
+}
+
+func TestBroker_WaitFor(t *testing.T) {
+	broker := memory.NewBroker(logger.NewDefaultLogger("error"))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_ = broker.Producer().Send(context.Background(), "channel", msg.NewMessage([]byte(`first`)))
+		_ = broker.Producer().Send(context.Background(), "channel", msg.NewMessage([]byte(`second`)))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	message, err := broker.WaitFor(ctx, "channel", func(message msg.Message) bool {
+		return string(message.Payload()) == "second"
+	})
+	if err != nil {
+		t.Fatalf("WaitFor() error = %v", err)
+	}
+	if string(message.Payload()) != "second" {
+		t.Errorf("WaitFor() payload = %s, want second", message.Payload())
+	}
+	if got := len(broker.Published("channel")); got != 2 {
+		t.Errorf("Published() count = %d, want 2", got)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err = broker.WaitFor(ctx, "other", nil); err == nil {
+		t.Errorf("WaitFor() error = nil, want a timeout")
+	}
+}
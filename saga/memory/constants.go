@@ -0,0 +1,12 @@
+package memory
+
+import (
+	"time"
+)
+
+const (
+	DefaultRedeliveryDelay = 10 * time.Millisecond
+	DefaultMaxDeliveries   = 5
+
+	DefaultSagaInstanceListLimit = 100
+)
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nguyenta1993/service-kit/saga/msg"
+)
+
+type consumer struct {
+	broker *Broker
+	group  string
+	closed chan struct{}
+	close  sync.Once
+}
+
+var _ msg.Consumer = (*consumer)(nil)
+
+// Listen receives the messages of the channel until the context is done or the consumer is closed
+func (c *consumer) Listen(ctx context.Context, channel string, fn msg.ReceiveMessageFunc) error {
+	cur := c.broker.cursor(c.group, channel)
+
+	for {
+		// the token is held while a message is received so the listeners of a group receive messages in order
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.closed:
+			return nil
+		case cur.token <- struct{}{}:
+		}
+
+		message, updated := c.broker.next(channel, cur.offset)
+		if message == nil {
+			<-cur.token
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-c.closed:
+				return nil
+			case <-updated:
+			}
+
+			continue
+		}
+
+		if c.broker.deliver(ctx, channel, c.group, message, fn) {
+			cur.offset++
+		}
+
+		<-cur.token
+	}
+}
+
+func (c *consumer) Close(context.Context) error {
+	c.close.Do(func() {
+		close(c.closed)
+	})
+
+	return nil
+}
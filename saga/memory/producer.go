@@ -0,0 +1,23 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/nguyenta1993/service-kit/saga/msg"
+)
+
+type producer struct {
+	broker *Broker
+}
+
+var _ msg.Producer = (*producer)(nil)
+
+func (p *producer) Send(_ context.Context, channel string, message msg.Message) error {
+	p.broker.send(channel, message)
+
+	return nil
+}
+
+func (p *producer) Close(context.Context) error {
+	return nil
+}
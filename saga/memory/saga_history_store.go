@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nguyenta1993/service-kit/saga/saga"
+)
+
+// SagaHistoryStore is an in-memory saga.HistoryStore for tests and local development
+type SagaHistoryStore struct {
+	mu      sync.Mutex
+	records map[string][]saga.HistoryRecord
+}
+
+var _ saga.HistoryStore = (*SagaHistoryStore)(nil)
+
+// NewSagaHistoryStore constructs a new SagaHistoryStore
+func NewSagaHistoryStore() *SagaHistoryStore {
+	return &SagaHistoryStore{
+		records: map[string][]saga.HistoryRecord{},
+	}
+}
+
+func (s *SagaHistoryStore) Append(_ context.Context, record saga.HistoryRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := record.SagaName + "/" + record.SagaID
+	s.records[key] = append(s.records[key], record)
+
+	return nil
+}
+
+func (s *SagaHistoryStore) Timeline(_ context.Context, sagaName, sagaID string) ([]saga.HistoryRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]saga.HistoryRecord{}, s.records[sagaName+"/"+sagaID]...), nil
+}
@@ -0,0 +1,272 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nguyenta1993/service-kit/saga/core"
+	"github.com/nguyenta1993/service-kit/saga/saga"
+)
+
+// SagaInstanceStore is an in-memory saga.InstanceStore for tests and local development
+type SagaInstanceStore struct {
+	mu        sync.Mutex
+	instances map[string]map[string]instanceRecord
+}
+
+type instanceRecord struct {
+	sagaName          string
+	sagaID            string
+	sagaDataName      string
+	sagaData          []byte
+	currentStep       int
+	endState          bool
+	compensating      bool
+	deadline          time.Time
+	stepDeadline      time.Time
+	version           int
+	modifiedAt        time.Time
+	executionState    []byte
+	correlationKey    string
+	parentSagaName    string
+	parentSagaID      string
+	definitionVersion int
+}
+
+var _ saga.InstanceStore = (*SagaInstanceStore)(nil)
+var _ saga.OverdueFinder = (*SagaInstanceStore)(nil)
+var _ saga.InstanceLister = (*SagaInstanceStore)(nil)
+var _ saga.CorrelationKeyFinder = (*SagaInstanceStore)(nil)
+var _ saga.ChildFinder = (*SagaInstanceStore)(nil)
+
+// NewSagaInstanceStore constructs a new SagaInstanceStore
+func NewSagaInstanceStore() *SagaInstanceStore {
+	return &SagaInstanceStore{
+		instances: map[string]map[string]instanceRecord{},
+	}
+}
+
+func (s *SagaInstanceStore) Find(_ context.Context, sagaName, sagaID string) (*saga.Instance, error) {
+	s.mu.Lock()
+	record, exists := s.instances[sagaName][sagaID]
+	s.mu.Unlock()
+
+	if !exists {
+		return nil, saga.ErrInstanceNotFound
+	}
+
+	return record.instance()
+}
+
+func (s *SagaInstanceStore) FindOverdue(_ context.Context, sagaName string, now time.Time, limit int) ([]*saga.Instance, error) {
+	return s.find(func(record instanceRecord) bool {
+		if record.sagaName != sagaName || record.endState {
+			return false
+		}
+
+		if !record.stepDeadline.IsZero() && record.stepDeadline.Before(now) {
+			return true
+		}
+
+		return !record.compensating && !record.deadline.IsZero() && record.deadline.Before(now)
+	}, 0, limit)
+}
+
+func (s *SagaInstanceStore) List(_ context.Context, query saga.InstanceQuery) ([]*saga.Instance, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultSagaInstanceListLimit
+	}
+
+	return s.find(func(record instanceRecord) bool {
+		if query.SagaName != "" && record.sagaName != query.SagaName {
+			return false
+		}
+
+		if !query.ModifiedBefore.IsZero() && !record.modifiedAt.Before(query.ModifiedBefore) {
+			return false
+		}
+
+		if query.DefinitionVersion != 0 && record.definitionVersion != query.DefinitionVersion {
+			return false
+		}
+
+		switch query.State {
+		case saga.InstanceRunning:
+			return !record.endState && !record.compensating
+		case saga.InstanceCompensating:
+			return !record.endState && record.compensating
+		case saga.InstanceEnded:
+			return record.endState
+		}
+
+		return true
+	}, query.Offset, limit)
+}
+
+func (s *SagaInstanceStore) FindByCorrelationKey(_ context.Context, sagaName, correlationKey string) (*saga.Instance, error) {
+	instances, err := s.find(func(record instanceRecord) bool {
+		return record.sagaName == sagaName && !record.endState && record.correlationKey != "" && record.correlationKey == correlationKey
+	}, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(instances) == 0 {
+		return nil, saga.ErrInstanceNotFound
+	}
+
+	return instances[0], nil
+}
+
+func (s *SagaInstanceStore) FindChildren(_ context.Context, parentSagaName, parentSagaID string) ([]*saga.Instance, error) {
+	return s.find(func(record instanceRecord) bool {
+		return record.parentSagaID != "" && record.parentSagaName == parentSagaName && record.parentSagaID == parentSagaID
+	}, 0, 0)
+}
+
+func (s *SagaInstanceStore) Save(_ context.Context, sagaInstance *saga.Instance) error {
+	record, err := newInstanceRecord(sagaInstance)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.instances[record.sagaName][record.sagaID]; exists {
+		return fmt.Errorf("saga instance %s/%s already exists", record.sagaName, record.sagaID)
+	}
+
+	if _, exists := s.instances[record.sagaName]; !exists {
+		s.instances[record.sagaName] = map[string]instanceRecord{}
+	}
+
+	s.instances[record.sagaName][record.sagaID] = record
+
+	return nil
+}
+
+func (s *SagaInstanceStore) Update(_ context.Context, sagaInstance *saga.Instance) error {
+	record, err := newInstanceRecord(sagaInstance)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.instances[record.sagaName][record.sagaID]
+	if !exists {
+		return saga.ErrInstanceNotFound
+	}
+
+	if existing.version != record.version {
+		return &saga.ErrInstanceConflict{
+			SagaName: record.sagaName,
+			SagaID:   record.sagaID,
+			Version:  record.version,
+		}
+	}
+
+	record.version++
+	s.instances[record.sagaName][record.sagaID] = record
+
+	return nil
+}
+
+// find returns the instances matching the predicate ordered by the time they were last modified
+func (s *SagaInstanceStore) find(predicate func(record instanceRecord) bool, offset, limit int) ([]*saga.Instance, error) {
+	s.mu.Lock()
+	var records []instanceRecord
+	for _, instances := range s.instances {
+		for _, record := range instances {
+			if predicate(record) {
+				records = append(records, record)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].modifiedAt.After(records[j].modifiedAt)
+	})
+
+	if offset >= len(records) {
+		return nil, nil
+	}
+	records = records[offset:]
+
+	if limit > 0 && limit < len(records) {
+		records = records[:limit]
+	}
+
+	instances := make([]*saga.Instance, 0, len(records))
+	for _, record := range records {
+		instance, err := record.instance()
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, instance)
+	}
+
+	return instances, nil
+}
+
+func newInstanceRecord(sagaInstance *saga.Instance) (instanceRecord, error) {
+	data, err := core.SerializeSagaData(sagaInstance.SagaData())
+	if err != nil {
+		return instanceRecord{}, err
+	}
+
+	state, err := json.Marshal(sagaInstance.ExecutionState())
+	if err != nil {
+		return instanceRecord{}, err
+	}
+
+	return instanceRecord{
+		sagaName:          sagaInstance.SagaName(),
+		sagaID:            sagaInstance.SagaID(),
+		sagaDataName:      core.VersionedSagaDataName(sagaInstance.SagaData()),
+		sagaData:          data,
+		currentStep:       sagaInstance.CurrentStep(),
+		endState:          sagaInstance.EndState(),
+		compensating:      sagaInstance.Compensating(),
+		deadline:          sagaInstance.Deadline(),
+		stepDeadline:      sagaInstance.StepDeadline(),
+		version:           sagaInstance.Version(),
+		modifiedAt:        time.Now(),
+		executionState:    state,
+		correlationKey:    sagaInstance.CorrelationKey(),
+		parentSagaName:    sagaInstance.ParentSagaName(),
+		parentSagaID:      sagaInstance.ParentSagaID(),
+		definitionVersion: sagaInstance.DefinitionVersion(),
+	}, nil
+}
+
+func (r instanceRecord) instance() (*saga.Instance, error) {
+	sagaData, err := core.DeserializeSagaData(r.sagaDataName, r.sagaData)
+	if err != nil {
+		return nil, err
+	}
+
+	var executionState saga.ExecutionState
+	if err = json.Unmarshal(r.executionState, &executionState); err != nil {
+		return nil, err
+	}
+
+	return saga.NewSagaInstance(r.sagaName, r.sagaID, sagaData, r.currentStep, r.endState, r.compensating,
+		saga.WithInstanceDeadline(r.deadline),
+		saga.WithInstanceStepDeadline(r.stepDeadline),
+		saga.WithInstanceVersion(r.version),
+		saga.WithInstanceModifiedAt(r.modifiedAt),
+		saga.WithInstanceExecutionState(executionState),
+		saga.WithInstanceCorrelationKey(r.correlationKey),
+		saga.WithInstanceParent(r.parentSagaName, r.parentSagaID),
+		saga.WithInstanceDefinitionVersion(r.definitionVersion),
+	), nil
+}
@@ -0,0 +1,6 @@
+package mongo
+
+const (
+	DefaultSagaInstanceCollectionName = "saga_instances"
+	DefaultSagaInstanceListLimit      = 100
+)
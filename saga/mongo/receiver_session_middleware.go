@@ -0,0 +1,63 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+	"go.uber.org/zap"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ReceiverSessionMiddleware runs each message in a transaction, which requires a replica set
+func ReceiverSessionMiddleware(client *mongo.Client, logger logger.Logger) func(msg.MessageReceiver) msg.MessageReceiver {
+	return func(next msg.MessageReceiver) msg.MessageReceiver {
+		return msg.ReceiveMessageFunc(func(ctx context.Context, message msg.Message) (err error) {
+			var session mongo.Session
+
+			session, err = client.StartSession()
+			if err != nil {
+				logger.Error("error while starting the request session", zap.Error(err))
+				return fmt.Errorf("failed to start session: %s", err.Error())
+			}
+			defer session.EndSession(ctx)
+
+			err = session.StartTransaction()
+			if err != nil {
+				logger.Error("error while starting the request transaction", zap.Error(err))
+				return fmt.Errorf("failed to start transaction: %s", err.Error())
+			}
+
+			txCtx := mongo.NewSessionContext(ctx, session)
+
+			defer func() {
+				p := recover()
+				switch {
+				case p != nil:
+					txErr := session.AbortTransaction(ctx)
+					if txErr != nil {
+						logger.Error("error while aborting the message receiver transaction during panic", zap.Error(txErr))
+					}
+					panic(p)
+				case err != nil:
+					txErr := session.AbortTransaction(ctx)
+					if txErr != nil {
+						logger.Error("error while aborting the message receiver transaction", zap.Error(txErr))
+					}
+				default:
+					// the message must be received again when nothing has been persisted
+					err = session.CommitTransaction(ctx)
+					if err != nil {
+						logger.Error("error while committing the message receiver transaction", zap.Error(err))
+					}
+				}
+			}()
+
+			err = next.ReceiveMessage(txCtx, message)
+
+			return err
+		})
+	}
+}
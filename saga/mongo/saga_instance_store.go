@@ -0,0 +1,329 @@
+package mongo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/core"
+	"github.com/nguyenta1993/service-kit/saga/saga"
+)
+
+// SagaInstanceStore is a saga.InstanceStore saving instances into a MongoDB collection
+type SagaInstanceStore struct {
+	collectionName string
+	database       *mongo.Database
+	logger         logger.Logger
+}
+
+type instanceDocument struct {
+	ID             string     `bson:"_id"`
+	SagaName       string     `bson:"saga_name"`
+	SagaID         string     `bson:"saga_id"`
+	SagaDataName   string     `bson:"saga_data_name"`
+	SagaData       []byte     `bson:"saga_data"`
+	CurrentStep    int        `bson:"current_step"`
+	EndState       bool       `bson:"end_state"`
+	Compensating   bool       `bson:"compensating"`
+	Deadline       *time.Time `bson:"deadline,omitempty"`
+	StepDeadline   *time.Time `bson:"step_deadline,omitempty"`
+	Version        int        `bson:"version"`
+	ModifiedAt     time.Time  `bson:"modified_at"`
+	ExecutionState []byte     `bson:"execution_state,omitempty"`
+	CorrelationKey string     `bson:"correlation_key,omitempty"`
+	ParentSagaName string     `bson:"parent_saga_name,omitempty"`
+	ParentSagaID   string     `bson:"parent_saga_id,omitempty"`
+	// DefinitionVersion is missing from documents saved before definitions had versions; they are version 1
+	DefinitionVersion int `bson:"definition_version,omitempty"`
+}
+
+var _ saga.InstanceStore = (*SagaInstanceStore)(nil)
+var _ saga.OverdueFinder = (*SagaInstanceStore)(nil)
+var _ saga.InstanceLister = (*SagaInstanceStore)(nil)
+var _ saga.CorrelationKeyFinder = (*SagaInstanceStore)(nil)
+var _ saga.ChildFinder = (*SagaInstanceStore)(nil)
+
+// NewSagaInstanceStore constructs a new SagaInstanceStore
+func NewSagaInstanceStore(logger logger.Logger, database *mongo.Database, options ...SagaInstanceStoreOption) *SagaInstanceStore {
+	s := &SagaInstanceStore{
+		collectionName: DefaultSagaInstanceCollectionName,
+		database:       database,
+		logger:         logger,
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+// CreateIndexes creates the indexes used to find overdue, correlated, child and list instances
+func (s *SagaInstanceStore) CreateIndexes(ctx context.Context) error {
+	_, err := s.collection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "saga_name", Value: 1}, {Key: "end_state", Value: 1}, {Key: "modified_at", Value: 1}}},
+		{Keys: bson.D{{Key: "modified_at", Value: 1}}},
+		{Keys: bson.D{{Key: "saga_name", Value: 1}, {Key: "correlation_key", Value: 1}}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.D{{Key: "parent_saga_name", Value: 1}, {Key: "parent_saga_id", Value: 1}}, Options: options.Index().SetSparse(true)},
+	})
+
+	return err
+}
+
+func (s *SagaInstanceStore) Find(ctx context.Context, sagaName, sagaID string) (*saga.Instance, error) {
+	var document instanceDocument
+
+	err := s.collection().FindOne(ctx, bson.M{"_id": documentID(sagaName, sagaID)}).Decode(&document)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, saga.ErrInstanceNotFound
+		}
+		return nil, err
+	}
+
+	return document.instance()
+}
+
+func (s *SagaInstanceStore) FindByCorrelationKey(ctx context.Context, sagaName, correlationKey string) (*saga.Instance, error) {
+	var document instanceDocument
+
+	err := s.collection().FindOne(ctx,
+		bson.M{"saga_name": sagaName, "correlation_key": correlationKey, "end_state": false},
+		options.FindOne().SetSort(bson.D{{Key: "modified_at", Value: -1}}),
+	).Decode(&document)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, saga.ErrInstanceNotFound
+		}
+		return nil, err
+	}
+
+	return document.instance()
+}
+
+func (s *SagaInstanceStore) FindChildren(ctx context.Context, parentSagaName, parentSagaID string) ([]*saga.Instance, error) {
+	return s.find(ctx,
+		bson.M{"parent_saga_name": parentSagaName, "parent_saga_id": parentSagaID},
+		options.Find().SetSort(bson.D{{Key: "modified_at", Value: 1}}),
+	)
+}
+
+func (s *SagaInstanceStore) FindOverdue(ctx context.Context, sagaName string, now time.Time, limit int) ([]*saga.Instance, error) {
+	filter := bson.M{
+		"saga_name": sagaName,
+		"end_state": false,
+		"$or": bson.A{
+			bson.M{"step_deadline": bson.M{"$lt": now}},
+			bson.M{"compensating": false, "deadline": bson.M{"$lt": now}},
+		},
+	}
+
+	return s.find(ctx, filter, options.Find().SetSort(bson.D{{Key: "modified_at", Value: 1}}).SetLimit(int64(limit)))
+}
+
+func (s *SagaInstanceStore) List(ctx context.Context, query saga.InstanceQuery) ([]*saga.Instance, error) {
+	filter := bson.M{}
+
+	if query.SagaName != "" {
+		filter["saga_name"] = query.SagaName
+	}
+
+	switch query.State {
+	case saga.InstanceRunning:
+		filter["end_state"], filter["compensating"] = false, false
+	case saga.InstanceCompensating:
+		filter["end_state"], filter["compensating"] = false, true
+	case saga.InstanceEnded:
+		filter["end_state"] = true
+	}
+
+	if !query.ModifiedBefore.IsZero() {
+		filter["modified_at"] = bson.M{"$lt": query.ModifiedBefore}
+	}
+
+	switch {
+	case query.DefinitionVersion == 1:
+		filter["definition_version"] = bson.M{"$in": bson.A{1, nil}}
+	case query.DefinitionVersion != 0:
+		filter["definition_version"] = query.DefinitionVersion
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultSagaInstanceListLimit
+	}
+
+	return s.find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "modified_at", Value: -1}}).
+		SetSkip(int64(query.Offset)).
+		SetLimit(int64(limit)),
+	)
+}
+
+func (s *SagaInstanceStore) Save(ctx context.Context, sagaInstance *saga.Instance) error {
+	document, err := newInstanceDocument(sagaInstance)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.collection().InsertOne(ctx, document)
+	return err
+}
+
+func (s *SagaInstanceStore) Update(ctx context.Context, sagaInstance *saga.Instance) error {
+	document, err := newInstanceDocument(sagaInstance)
+	if err != nil {
+		return err
+	}
+
+	set := bson.M{
+		"saga_data_name":  document.SagaDataName,
+		"saga_data":       document.SagaData,
+		"current_step":    document.CurrentStep,
+		"end_state":       document.EndState,
+		"compensating":    document.Compensating,
+		"deadline":        document.Deadline,
+		"step_deadline":   document.StepDeadline,
+		"execution_state": document.ExecutionState,
+		"modified_at":     document.ModifiedAt,
+	}
+
+	update := bson.M{"$set": set, "$inc": bson.M{"version": 1}}
+
+	// the key is removed rather than blanked so the sparse index only holds waiting instances
+	if document.CorrelationKey != "" {
+		set["correlation_key"] = document.CorrelationKey
+	} else {
+		update["$unset"] = bson.M{"correlation_key": ""}
+	}
+
+	result, err := s.collection().UpdateOne(ctx,
+		bson.M{"_id": document.ID, "version": document.Version},
+		update,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return &saga.ErrInstanceConflict{
+			SagaName: sagaInstance.SagaName(),
+			SagaID:   sagaInstance.SagaID(),
+			Version:  sagaInstance.Version(),
+		}
+	}
+
+	return nil
+}
+
+func (s *SagaInstanceStore) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]*saga.Instance, error) {
+	cursor, err := s.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var instances []*saga.Instance
+
+	for cursor.Next(ctx) {
+		var document instanceDocument
+		if err = cursor.Decode(&document); err != nil {
+			return nil, err
+		}
+
+		instance, err := document.instance()
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, instance)
+	}
+
+	return instances, cursor.Err()
+}
+
+func (s *SagaInstanceStore) collection() *mongo.Collection {
+	return s.database.Collection(s.collectionName)
+}
+
+func newInstanceDocument(sagaInstance *saga.Instance) (instanceDocument, error) {
+	data, err := core.SerializeSagaData(sagaInstance.SagaData())
+	if err != nil {
+		return instanceDocument{}, err
+	}
+
+	state, err := json.Marshal(sagaInstance.ExecutionState())
+	if err != nil {
+		return instanceDocument{}, err
+	}
+
+	return instanceDocument{
+		ID:                documentID(sagaInstance.SagaName(), sagaInstance.SagaID()),
+		SagaName:          sagaInstance.SagaName(),
+		SagaID:            sagaInstance.SagaID(),
+		SagaDataName:      core.VersionedSagaDataName(sagaInstance.SagaData()),
+		SagaData:          data,
+		CurrentStep:       sagaInstance.CurrentStep(),
+		EndState:          sagaInstance.EndState(),
+		Compensating:      sagaInstance.Compensating(),
+		Deadline:          nullTime(sagaInstance.Deadline()),
+		StepDeadline:      nullTime(sagaInstance.StepDeadline()),
+		Version:           sagaInstance.Version(),
+		ModifiedAt:        time.Now(),
+		ExecutionState:    state,
+		CorrelationKey:    sagaInstance.CorrelationKey(),
+		ParentSagaName:    sagaInstance.ParentSagaName(),
+		ParentSagaID:      sagaInstance.ParentSagaID(),
+		DefinitionVersion: sagaInstance.DefinitionVersion(),
+	}, nil
+}
+
+func (d instanceDocument) instance() (*saga.Instance, error) {
+	sagaData, err := core.DeserializeSagaData(d.SagaDataName, d.SagaData)
+	if err != nil {
+		return nil, err
+	}
+
+	var executionState saga.ExecutionState
+	if len(d.ExecutionState) > 0 {
+		if err = json.Unmarshal(d.ExecutionState, &executionState); err != nil {
+			return nil, err
+		}
+	}
+
+	return saga.NewSagaInstance(d.SagaName, d.SagaID, sagaData, d.CurrentStep, d.EndState, d.Compensating,
+		saga.WithInstanceDeadline(timeValue(d.Deadline)),
+		saga.WithInstanceStepDeadline(timeValue(d.StepDeadline)),
+		saga.WithInstanceVersion(d.Version),
+		saga.WithInstanceModifiedAt(d.ModifiedAt),
+		saga.WithInstanceExecutionState(executionState),
+		saga.WithInstanceCorrelationKey(d.CorrelationKey),
+		saga.WithInstanceParent(d.ParentSagaName, d.ParentSagaID),
+		saga.WithInstanceDefinitionVersion(d.DefinitionVersion),
+	), nil
+}
+
+func documentID(sagaName, sagaID string) string {
+	return sagaName + "/" + sagaID
+}
+
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
+
+func timeValue(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+
+	return *t
+}
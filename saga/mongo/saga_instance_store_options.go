@@ -0,0 +1,17 @@
+package mongo
+
+import "github.com/nguyenta1993/service-kit/logger"
+
+type SagaInstanceStoreOption func(*SagaInstanceStore)
+
+func WithSagaInstanceStoreCollectionName(collectionName string) SagaInstanceStoreOption {
+	return func(store *SagaInstanceStore) {
+		store.collectionName = collectionName
+	}
+}
+
+func WithSagaInstanceStoreLogger(logger logger.Logger) SagaInstanceStoreOption {
+	return func(store *SagaInstanceStore) {
+		store.logger = logger
+	}
+}
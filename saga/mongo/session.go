@@ -0,0 +1,64 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/saga"
+)
+
+// NewSession returns a saga.Session that joins the transaction in the context or runs in a transaction of its own
+func NewSession(client *mongo.Client, logger logger.Logger) saga.Session {
+	return func(ctx context.Context, fn func(context.Context) error) (err error) {
+		if mongo.SessionFromContext(ctx) != nil {
+			err = fn(ctx)
+
+			// transactions cannot be nested; the message is received again instead of retrying in the same snapshot
+			var conflict *saga.ErrInstanceConflict
+			if errors.As(err, &conflict) {
+				return fmt.Errorf("saga instance conflict in the receiver transaction: %v", err)
+			}
+
+			return err
+		}
+
+		var session mongo.Session
+
+		session, err = client.StartSession()
+		if err != nil {
+			logger.Error("error while starting the saga session", zap.Error(err))
+			return err
+		}
+		defer session.EndSession(ctx)
+
+		err = session.StartTransaction()
+		if err != nil {
+			logger.Error("error while starting the saga session transaction", zap.Error(err))
+			return err
+		}
+
+		defer func() {
+			p := recover()
+			switch {
+			case p != nil:
+				if txErr := session.AbortTransaction(ctx); txErr != nil {
+					logger.Error("error while aborting the saga session transaction during panic", zap.Error(txErr))
+				}
+				panic(p)
+			case err != nil:
+				if txErr := session.AbortTransaction(ctx); txErr != nil {
+					logger.Error("error while aborting the saga session transaction", zap.Error(txErr))
+				}
+			default:
+				err = session.CommitTransaction(ctx)
+			}
+		}()
+
+		return fn(mongo.NewSessionContext(ctx, session))
+	}
+}
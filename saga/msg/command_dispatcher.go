@@ -70,7 +70,7 @@ func (d *CommandDispatcher) ReceiveMessage(ctx context.Context, message Message)
 
 	logger.Info("command handler found")
 
-	command, err := core.DeserializeCommand(commandName, message.Payload())
+	command, err := core.DeserializeCommand(core.VersionedName(commandName, SchemaVersion(message.Headers())), message.Payload())
 	if err != nil {
 		logger.Error("error decoding command message payload", zap.Error(err))
 		return nil
@@ -124,7 +124,7 @@ func (d *CommandDispatcher) sendReplies(ctx context.Context, replyChannel string
 func (d *CommandDispatcher) correlationHeaders(headers Headers) Headers {
 	replyHeaders := make(map[string]string)
 	for key, value := range headers {
-		if key == MessageCommandName {
+		if key == MessageCommandName || strings.HasPrefix(key, MessageCommandTracePrefix) {
 			continue
 		}
 
@@ -1,5 +1,9 @@
 package msg
 
+import (
+	"time"
+)
+
 // Message header keys
 const (
 	MessageID            = "ID"
@@ -7,6 +11,7 @@ const (
 	MessageChannel       = "CHANNEL"
 	MessageCorrelationID = "CORRELATION_ID"
 	MessageCausationID   = "CAUSATION_ID"
+	MessageSchemaVersion = "SCHEMA_VERSION"
 
 	MessageEventPrefix     = "EVENT_"
 	MessageEventName       = MessageEventPrefix + "NAME"
@@ -17,8 +22,22 @@ const (
 	MessageCommandName         = MessageCommandPrefix + "NAME"
 	MessageCommandChannel      = MessageCommandPrefix + "CHANNEL"
 	MessageCommandReplyChannel = MessageCommandPrefix + "REPLY_CHANNEL"
+	// MessageCommandRequestID correlates the commands sent with Publisher.SendCommand to their reply
+	MessageCommandRequestID = MessageCommandPrefix + "REQUEST_ID"
+	// MessageCommandTracePrefix prefixes the trace context headers of commands; they are not echoed in replies
+	MessageCommandTracePrefix = MessageCommandPrefix + "TRACE_"
 
 	MessageReplyPrefix  = "REPLY_"
 	MessageReplyName    = MessageReplyPrefix + "NAME"
 	MessageReplyOutcome = MessageReplyPrefix + "OUTCOME"
+	// MessageReplyRequestID echoes MessageCommandRequestID
+	MessageReplyRequestID = MessageReplyPrefix + "REQUEST_ID"
+	// MessageReplyTracePrefix prefixes the trace context headers of replies
+	MessageReplyTracePrefix = MessageReplyPrefix + "TRACE_"
 )
+
+// DefaultReplyTimeout is how long Publisher.SendCommand waits for a reply
+const DefaultReplyTimeout = 30 * time.Second
+
+// DefaultReplyChannelPrefix prefixes the reply channel generated for each Publisher
+const DefaultReplyChannelPrefix = "replies."
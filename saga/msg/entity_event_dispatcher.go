@@ -80,7 +80,7 @@ func (d *EntityEventDispatcher) ReceiveMessage(ctx context.Context, message Mess
 
 	logger.Info("entity event handler found")
 
-	event, err := core.DeserializeEvent(eventName, message.Payload())
+	event, err := core.DeserializeEvent(core.VersionedName(eventName, SchemaVersion(message.Headers())), message.Payload())
 	if err != nil {
 		logger.Error("error decoding entity event message payload", zap.Error(err))
 		return nil
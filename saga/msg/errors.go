@@ -0,0 +1,15 @@
+package msg
+
+import (
+	"errors"
+)
+
+// Publisher.SendCommand errors
+var (
+	ErrReplyTimeout = errors.New("no reply was received before the timeout")
+)
+
+// Middleware errors
+var (
+	ErrReceiverNotNamed = errors.New("the receiver is not a msg.NamedMessageReceiver")
+)
@@ -66,7 +66,7 @@ func (d *EventDispatcher) ReceiveMessage(ctx context.Context, message Message) e
 
 	logger.Info("event handler found")
 
-	event, err := core.DeserializeEvent(eventName, message.Payload())
+	event, err := core.DeserializeEvent(core.VersionedName(eventName, SchemaVersion(message.Headers())), message.Payload())
 	if err != nil {
 		logger.Error("error decoding event message payload", zap.Error(err))
 		return nil
@@ -2,6 +2,7 @@ package msg
 
 import (
 	"fmt"
+	"strconv"
 )
 
 // Headers a map of strings keyed by Message header keys
@@ -33,3 +34,13 @@ func (h Headers) GetRequired(key string) (string, error) {
 func (h Headers) Set(key, value string) {
 	h[key] = value
 }
+
+// SchemaVersion returns the schema version of the message payload; messages without the header are at version 1
+func SchemaVersion(headers Headers) int {
+	version, err := strconv.Atoi(headers.Get(MessageSchemaVersion))
+	if err != nil || version < 1 {
+		return 1
+	}
+
+	return version
+}
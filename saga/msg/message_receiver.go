@@ -16,3 +16,27 @@ type ReceiveMessageFunc func(context.Context, Message) error
 func (f ReceiveMessageFunc) ReceiveMessage(ctx context.Context, message Message) error {
 	return f(ctx, message)
 }
+
+// NamedMessageReceiver is implemented by receivers that provide a stable name to middleware
+type NamedMessageReceiver interface {
+	MessageReceiver
+	ReceiverName() string
+}
+
+type namedReceiver struct {
+	MessageReceiver
+	name string
+}
+
+// NamedReceiver gives the receiver a name for middleware that requires a NamedMessageReceiver
+func NamedReceiver(name string, receiver MessageReceiver) NamedMessageReceiver {
+	return namedReceiver{
+		MessageReceiver: receiver,
+		name:            name,
+	}
+}
+
+// ReceiverName implements NamedMessageReceiver.ReceiverName
+func (r namedReceiver) ReceiverName() string {
+	return r.name
+}
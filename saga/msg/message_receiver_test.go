@@ -47,3 +47,23 @@ func TestReceiveMessageFunc_ReceiveMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestNamedReceiver(t *testing.T) {
+	received := false
+	receiver := msg.NamedReceiver("orders-commands", msg.ReceiveMessageFunc(func(ctx context.Context, m msg.Message) error {
+		received = true
+		return nil
+	}))
+
+	if got := receiver.ReceiverName(); got != "orders-commands" {
+		t.Errorf("ReceiverName() = %v, want %v", got, "orders-commands")
+	}
+
+	if err := receiver.ReceiveMessage(context.Background(), msg.NewMessage([]byte(`{}`))); err != nil {
+		t.Errorf("ReceiveMessage() error = %v", err)
+	}
+
+	if !received {
+		t.Errorf("ReceiveMessage() did not call the receiver")
+	}
+}
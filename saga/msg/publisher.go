@@ -2,9 +2,13 @@ package msg
 
 import (
 	"context"
+	"os"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/nguyenta1993/service-kit/logger"
 	"github.com/nguyenta1993/service-kit/saga/core"
 	"go.uber.org/zap"
@@ -46,13 +50,21 @@ type Publisher struct {
 	producer Producer
 	logger   logger.Logger
 	close    sync.Once
+	// replyChannel receives the replies to SendCommand; pending holds their callers by request ID
+	replyChannel     string
+	replyChannelOnce sync.Once
+	replyTimeout     time.Duration
+	pendingMu        sync.Mutex
+	pending          map[string]chan Reply
 }
 
 // NewPublisher constructs a new Publisher
 func NewPublisher(producer Producer, logger logger.Logger, options ...PublisherOption) *Publisher {
 	p := &Publisher{
-		producer: producer,
-		logger:   logger,
+		producer:     producer,
+		logger:       logger,
+		replyTimeout: DefaultReplyTimeout,
+		pending:      map[string]chan Reply{},
 	}
 
 	for _, option := range options {
@@ -70,6 +82,7 @@ func (p *Publisher) PublishCommand(ctx context.Context, replyChannel string, com
 		WithHeaders(map[string]string{
 			MessageCommandName:         command.CommandName(),
 			MessageCommandReplyChannel: replyChannel,
+			MessageSchemaVersion:       strconv.Itoa(core.TypeVersion(command.CommandName())),
 		}),
 	}
 
@@ -101,11 +114,75 @@ func (p *Publisher) PublishCommand(ctx context.Context, replyChannel string, com
 	return err
 }
 
+// SendCommand publishes a command and waits for its reply; don't call it inside an outbox transaction
+func (p *Publisher) SendCommand(ctx context.Context, command core.Command, options ...MessageOption) (Reply, error) {
+	requestID := uuid.New().String()
+	replies := make(chan Reply, 1)
+
+	p.pendingMu.Lock()
+	p.pending[requestID] = replies
+	p.pendingMu.Unlock()
+
+	defer func() {
+		p.pendingMu.Lock()
+		delete(p.pending, requestID)
+		p.pendingMu.Unlock()
+	}()
+
+	options = append(options[:len(options):len(options)], WithHeaders(Headers{MessageCommandRequestID: requestID}))
+	if err := p.PublishCommand(ctx, p.ReplyChannel(), command, options...); err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(p.replyTimeout)
+	defer timer.Stop()
+
+	select {
+	case reply := <-replies:
+		return reply, nil
+	case <-timer.C:
+		p.logger.Warn("no reply received for command",
+			zap.String("CommandName", command.CommandName()),
+			zap.String("RequestID", requestID),
+		)
+		return nil, ErrReplyTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// ReplyChannel returns the channel the replies to SendCommand are sent to, generated on first use
+func (p *Publisher) ReplyChannel() string {
+	p.replyChannelOnce.Do(func() {
+		if p.replyChannel == "" {
+			p.replyChannel = newReplyChannel()
+		}
+	})
+
+	return p.replyChannel
+}
+
+// newReplyChannel returns a reply channel for a single publisher so replies are not consumed by other processes
+func newReplyChannel() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "unknown"
+	}
+
+	return DefaultReplyChannelPrefix + hostname + "." + uuid.New().String()[:8]
+}
+
+// ReplyReceiver returns the MessageReceiver to subscribe to the reply channel of the publisher
+func (p *Publisher) ReplyReceiver() MessageReceiver {
+	return replyReceiver{p}
+}
+
 // PublishReply serializes a reply into a message with reply specific headers and publishes it to a producer
 func (p *Publisher) PublishReply(ctx context.Context, reply core.Reply, options ...MessageOption) error {
 	msgOptions := []MessageOption{
 		WithHeaders(map[string]string{
-			MessageReplyName: reply.ReplyName(),
+			MessageReplyName:     reply.ReplyName(),
+			MessageSchemaVersion: strconv.Itoa(core.TypeVersion(reply.ReplyName())),
 		}),
 	}
 
@@ -173,7 +250,8 @@ func (p *Publisher) PublishEntityEvents(ctx context.Context, entity core.Entity,
 func (p *Publisher) PublishEvent(ctx context.Context, event core.Event, options ...MessageOption) error {
 	msgOptions := []MessageOption{
 		WithHeaders(map[string]string{
-			MessageEventName: event.EventName(),
+			MessageEventName:     event.EventName(),
+			MessageSchemaVersion: strconv.Itoa(core.TypeVersion(event.EventName())),
 		}),
 	}
 
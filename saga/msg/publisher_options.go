@@ -1,6 +1,10 @@
 package msg
 
-import "github.com/nguyenta1993/service-kit/logger"
+import (
+	"time"
+
+	"github.com/nguyenta1993/service-kit/logger"
+)
 
 // PublisherOption options for PublisherPublisher
 type PublisherOption func(*Publisher)
@@ -11,3 +15,17 @@ func WithPublisherLogger(logger logger.Logger) PublisherOption {
 		publisher.logger = logger
 	}
 }
+
+// WithPublisherReplyChannel is an option to set the reply channel, which no other publisher or instance may share
+func WithPublisherReplyChannel(channel string) PublisherOption {
+	return func(publisher *Publisher) {
+		publisher.replyChannel = channel
+	}
+}
+
+// WithPublisherReplyTimeout is an option to set how long SendCommand waits for a reply
+func WithPublisherReplyTimeout(timeout time.Duration) PublisherOption {
+	return func(publisher *Publisher) {
+		publisher.replyTimeout = timeout
+	}
+}
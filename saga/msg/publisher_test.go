@@ -0,0 +1,100 @@
+package msg_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/core"
+	"github.com/nguyenta1993/service-kit/saga/memory"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+	_ "github.com/nguyenta1993/service-kit/saga/msgpack"
+)
+
+type reserveStock struct{ Quantity int }
+
+func (reserveStock) CommandName() string        { return "msg_test.reserveStock" }
+func (reserveStock) DestinationChannel() string { return "inventory" }
+
+func init() {
+	core.RegisterCommands(reserveStock{})
+}
+
+func TestPublisher_SendCommand(t *testing.T) {
+	log := logger.NewDefaultLogger("error")
+
+	tests := map[string]struct {
+		handle  bool
+		wantErr error
+	}{
+		"Replied":  {handle: true},
+		"TimedOut": {handle: false, wantErr: msg.ErrReplyTimeout},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			broker := memory.NewBroker(log)
+			publisher := msg.NewPublisher(broker.Producer(), log, msg.WithPublisherReplyTimeout(100*time.Millisecond))
+
+			if tt.handle {
+				dispatcher := msg.NewCommandDispatcher(publisher, log).
+					Handle(reserveStock{}, func(context.Context, msg.Command) ([]msg.Reply, error) {
+						return []msg.Reply{msg.WithSuccess()}, nil
+					})
+
+				go func() {
+					command, err := broker.WaitFor(ctx, "inventory", nil)
+					if err != nil {
+						return
+					}
+					_ = dispatcher.ReceiveMessage(ctx, command)
+
+					reply, err := broker.WaitFor(ctx, publisher.ReplyChannel(), nil)
+					if err != nil {
+						return
+					}
+					_ = publisher.ReplyReceiver().ReceiveMessage(ctx, reply)
+				}()
+			}
+
+			reply, err := publisher.SendCommand(ctx, reserveStock{Quantity: 1})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SendCommand() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && reply.Headers().Get(msg.MessageReplyOutcome) != msg.ReplyOutcomeSuccess {
+				t.Errorf("SendCommand() outcome = %s, want %s", reply.Headers().Get(msg.MessageReplyOutcome), msg.ReplyOutcomeSuccess)
+			}
+		})
+	}
+}
+
+func TestPublisher_ReplyChannel(t *testing.T) {
+	log := logger.NewDefaultLogger("error")
+	broker := memory.NewBroker(log)
+
+	first := msg.NewPublisher(broker.Producer(), log)
+	second := msg.NewPublisher(broker.Producer(), log)
+
+	if !strings.HasPrefix(first.ReplyChannel(), msg.DefaultReplyChannelPrefix) {
+		t.Errorf("ReplyChannel() = %s, want prefix %s", first.ReplyChannel(), msg.DefaultReplyChannelPrefix)
+	}
+
+	if first.ReplyChannel() == second.ReplyChannel() {
+		t.Errorf("ReplyChannel() = %s for both publishers, want a channel for each", first.ReplyChannel())
+	}
+
+	channel := first.ReplyChannel()
+	if first.ReplyReceiver().(msg.NamedMessageReceiver).ReceiverName() != "msg.ReplyReceiver:"+channel {
+		t.Errorf("ReplyReceiver() is not named after %s, want the channel generated on first use", channel)
+	}
+
+	configured := msg.NewPublisher(broker.Producer(), log, msg.WithPublisherReplyChannel("replies.orders"))
+	if configured.ReplyChannel() != "replies.orders" {
+		t.Errorf("ReplyChannel() = %s, want replies.orders", configured.ReplyChannel())
+	}
+}
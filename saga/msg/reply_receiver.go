@@ -0,0 +1,64 @@
+package msg
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/nguyenta1993/service-kit/saga/core"
+)
+
+// replyReceiver delivers the replies on the reply channel of a Publisher to the callers of SendCommand
+type replyReceiver struct {
+	publisher *Publisher
+}
+
+var _ NamedMessageReceiver = (*replyReceiver)(nil)
+
+// ReceiverName implements NamedMessageReceiver.ReceiverName
+func (r replyReceiver) ReceiverName() string {
+	return "msg.ReplyReceiver:" + r.publisher.ReplyChannel()
+}
+
+// ReceiveMessage implements MessageReceiver.ReceiveMessage
+func (r replyReceiver) ReceiveMessage(_ context.Context, message Message) error {
+	requestID := message.Headers().Get(MessageReplyRequestID)
+	if requestID == "" {
+		return nil
+	}
+
+	logger := r.publisher.logger.With(
+		zap.String("RequestID", requestID),
+		zap.String("MessageID", message.ID()),
+	)
+
+	r.publisher.pendingMu.Lock()
+	replies, exists := r.publisher.pending[requestID]
+	r.publisher.pendingMu.Unlock()
+
+	// the caller stopped waiting, or the reply was redelivered
+	if !exists {
+		logger.Info("ignoring reply without a waiting caller")
+		return nil
+	}
+
+	replyName, err := message.Headers().GetRequired(MessageReplyName)
+	if err != nil {
+		logger.Error("error reading reply name", zap.Error(err))
+		return nil
+	}
+
+	reply, err := core.DeserializeReply(core.VersionedName(replyName, SchemaVersion(message.Headers())), message.Payload())
+	if err != nil {
+		logger.Error("error decoding reply message payload", zap.Error(err))
+		return nil
+	}
+
+	select {
+	case replies <- NewReply(reply, message.Headers()):
+	default:
+		logger.Info("ignoring duplicate reply")
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package pgx
 
 import (
 	"context"
+	"errors"
 
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
@@ -58,17 +59,38 @@ type Client interface {
 // 	return c.conn.BeginTx(ctx, txOptions)
 // }
 
-type sessionClient struct{}
+type sessionClient struct {
+	reader Client
+	writer Client
+}
 
 var _ Client = (*sessionClient)(nil)
 
 // NewSessionClient returns a pgx.Conn or pgxpool.Pool compatible client that uses an active transaction from context
-func NewSessionClient() Client {
-	return sessionClient{}
+//
+// Without a transaction every operation returns ErrTxNotInContext, unless a reader is set with
+// WithSessionClientReader; queries then use the reader while changes still require a transaction. With
+// WithSessionClientPool both queries and changes use the pool.
+func NewSessionClient(options ...SessionClientOption) Client {
+	c := &sessionClient{}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return *c
+}
+
+// WithTx returns a copy of the context carrying the transaction used by the session client
+func WithTx(ctx context.Context, tx pgx.Tx) context.Context {
+	return context.WithValue(ctx, pgxTxKey, tx)
 }
 
 func (c sessionClient) Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
 	tx, err := c.tx(ctx)
+	if errors.Is(err, ErrTxNotInContext) && c.writer != nil {
+		return c.writer.Exec(ctx, sql, arguments...)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +100,9 @@ func (c sessionClient) Exec(ctx context.Context, sql string, arguments ...interf
 
 func (c sessionClient) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
 	tx, err := c.tx(ctx)
+	if errors.Is(err, ErrTxNotInContext) && c.reader != nil {
+		return c.reader.Query(ctx, sql, args...)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -87,6 +112,9 @@ func (c sessionClient) Query(ctx context.Context, sql string, args ...interface{
 
 func (c sessionClient) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
 	tx, err := c.tx(ctx)
+	if errors.Is(err, ErrTxNotInContext) && c.reader != nil {
+		return c.reader.QueryRow(ctx, sql, args...)
+	}
 	if err != nil {
 		return rowError{err}
 	}
@@ -96,6 +124,9 @@ func (c sessionClient) QueryRow(ctx context.Context, sql string, args ...interfa
 
 func (c sessionClient) QueryFunc(ctx context.Context, sql string, args []interface{}, scans []interface{}, f func(pgx.QueryFuncRow) error) (pgconn.CommandTag, error) {
 	tx, err := c.tx(ctx)
+	if errors.Is(err, ErrTxNotInContext) && c.reader != nil {
+		return c.reader.QueryFunc(ctx, sql, args, scans, f)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -105,6 +136,9 @@ func (c sessionClient) QueryFunc(ctx context.Context, sql string, args []interfa
 
 func (c sessionClient) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
 	tx, err := c.tx(ctx)
+	if errors.Is(err, ErrTxNotInContext) && c.writer != nil {
+		return c.writer.SendBatch(ctx, b)
+	}
 	if err != nil {
 		return batchError{err}
 	}
@@ -114,6 +148,9 @@ func (c sessionClient) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchRes
 
 func (c sessionClient) Begin(ctx context.Context) (pgx.Tx, error) {
 	tx, err := c.tx(ctx)
+	if errors.Is(err, ErrTxNotInContext) && c.writer != nil {
+		return c.writer.Begin(ctx)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -121,8 +158,11 @@ func (c sessionClient) Begin(ctx context.Context) (pgx.Tx, error) {
 	return tx.Begin(ctx)
 }
 
-func (c sessionClient) BeginTx(ctx context.Context, _ pgx.TxOptions) (pgx.Tx, error) {
+func (c sessionClient) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
 	tx, err := c.tx(ctx)
+	if errors.Is(err, ErrTxNotInContext) && c.writer != nil {
+		return c.writer.BeginTx(ctx, txOptions)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,19 @@
+package pgx
+
+// SessionClientOption options for the session client
+type SessionClientOption func(*sessionClient)
+
+// WithSessionClientReader is an option to query with the reader, e.g. the pool, outside of transactions
+func WithSessionClientReader(reader Client) SessionClientOption {
+	return func(client *sessionClient) {
+		client.reader = reader
+	}
+}
+
+// WithSessionClientPool is an option to query and change with the pool outside of transactions
+func WithSessionClientPool(pool Client) SessionClientOption {
+	return func(client *sessionClient) {
+		client.reader = pool
+		client.writer = pool
+	}
+}
@@ -0,0 +1,65 @@
+package pgx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sagapgx "github.com/nguyenta1993/service-kit/saga/pgx"
+)
+
+func TestSessionClient(t *testing.T) {
+	tests := map[string]struct {
+		reader    bool
+		pool      bool
+		withTx    bool
+		wantErr   error
+		wantTx    bool
+		wantWrite bool
+	}{
+		"InTransaction":      {withTx: true, wantTx: true, wantWrite: true},
+		"ReadWithReader":     {reader: true},
+		"ReadWithoutReader":  {wantErr: sagapgx.ErrTxNotInContext},
+		"InTransactionFirst": {reader: true, withTx: true, wantTx: true, wantWrite: true},
+		"WriteWithPool":      {pool: true, wantWrite: true},
+		"PoolInTransaction":  {pool: true, withTx: true, wantTx: true, wantWrite: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			tx, reader := newFakeDB(), newFakeDB()
+
+			var options []sagapgx.SessionClientOption
+			if tt.reader {
+				options = append(options, sagapgx.WithSessionClientReader(reader))
+			}
+			if tt.pool {
+				options = append(options, sagapgx.WithSessionClientPool(reader))
+			}
+			client := sagapgx.NewSessionClient(options...)
+
+			if tt.withTx {
+				ctx = sagapgx.WithTx(ctx, tx)
+			}
+
+			_, err := client.Query(ctx, "SELECT 1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Query() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := len(tx.executed("SELECT 1")) == 1; got != tt.wantTx {
+				t.Errorf("queried in the transaction = %v, want %v", got, tt.wantTx)
+			}
+			if got, want := len(reader.executed("SELECT 1")) == 1, (tt.reader || tt.pool) && !tt.wantTx; got != want {
+				t.Errorf("queried with the reader = %v, want %v", got, want)
+			}
+
+			// changes require a transaction, or the pool
+			if _, err = client.Exec(ctx, "DELETE"); tt.wantWrite != (err == nil) {
+				t.Errorf("Exec() error = %v", err)
+			}
+			if got, want := len(reader.executed("DELETE")) == 1, tt.pool && !tt.wantTx; got != want {
+				t.Errorf("changed with the pool = %v, want %v", got, want)
+			}
+		})
+	}
+}
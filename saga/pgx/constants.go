@@ -8,22 +8,127 @@ type contextKey int
 
 const (
 	DefaultSagaInstanceTableName = "saga_instances"
+	DefaultOutboxTableName       = "outbox"
+	DefaultInboxTableName        = "inbox"
+	DefaultSagaHistoryTableName  = "saga_history"
 
-	CreateSagaInstancesTableSQL = `CREATE TABLE %s (
-    saga_name      text        NOT NULL,
-    saga_id        text        NOT NULL,
-    saga_data_name text        NOT NULL,
-    saga_data      bytea       NOT NULL,
-    current_step   int         NOT NULL,
-    end_state      boolean     NOT NULL,
-    compensating   boolean     NOT NULL,
-    modified_at    timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	DefaultSagaInstanceListLimit = 100
+
+	CreateSagaInstancesTableSQL = `CREATE TABLE %[1]s (
+    saga_name          text        NOT NULL,
+    saga_id            text        NOT NULL,
+    saga_data_name     text        NOT NULL,
+    saga_data          bytea       NOT NULL,
+    current_step       int         NOT NULL,
+    end_state          boolean     NOT NULL,
+    compensating       boolean     NOT NULL,
+    modified_at        timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deadline           timestamptz,
+    step_deadline      timestamptz,
+    version            int         NOT NULL DEFAULT 0,
+    execution_state    bytea,
+    correlation_key    text,
+    parent_saga_name   text,
+    parent_saga_id     text,
+    definition_version int         NOT NULL DEFAULT 1,
     PRIMARY KEY (saga_name, saga_id)
-)`
+);
+CREATE INDEX %[1]s_correlation_key_idx ON %[1]s (saga_name, correlation_key) WHERE correlation_key IS NOT NULL;
+CREATE INDEX %[1]s_parent_idx ON %[1]s (parent_saga_name, parent_saga_id) WHERE parent_saga_id IS NOT NULL`
+
+	// AlterSagaInstancesAddDeadlinesSQL adds the deadline columns to saga instance tables created before they existed
+	AlterSagaInstancesAddDeadlinesSQL = `ALTER TABLE %s
+    ADD COLUMN IF NOT EXISTS deadline      timestamptz,
+    ADD COLUMN IF NOT EXISTS step_deadline timestamptz`
+
+	// AlterSagaInstancesAddVersionSQL adds the version column to saga instance tables created before it existed
+	AlterSagaInstancesAddVersionSQL = `ALTER TABLE %s
+    ADD COLUMN IF NOT EXISTS version int NOT NULL DEFAULT 0`
+
+	// AlterSagaInstancesAddExecutionStateSQL adds the execution state column to saga instance tables created before it existed
+	AlterSagaInstancesAddExecutionStateSQL = `ALTER TABLE %s
+    ADD COLUMN IF NOT EXISTS execution_state bytea`
+
+	// AlterSagaInstancesAddCorrelationKeySQL adds the correlation key column and index to saga instance tables created before they existed
+	AlterSagaInstancesAddCorrelationKeySQL = `ALTER TABLE %[1]s
+    ADD COLUMN IF NOT EXISTS correlation_key text;
+CREATE INDEX IF NOT EXISTS %[1]s_correlation_key_idx ON %[1]s (saga_name, correlation_key) WHERE correlation_key IS NOT NULL`
+
+	// AlterSagaInstancesAddParentSQL adds the parent columns and index to saga instance tables created before they existed
+	AlterSagaInstancesAddParentSQL = `ALTER TABLE %[1]s
+    ADD COLUMN IF NOT EXISTS parent_saga_name text,
+    ADD COLUMN IF NOT EXISTS parent_saga_id   text;
+CREATE INDEX IF NOT EXISTS %[1]s_parent_idx ON %[1]s (parent_saga_name, parent_saga_id) WHERE parent_saga_id IS NOT NULL`
+
+	// AlterSagaInstancesAddDefinitionVersionSQL adds the definition version column to existing saga instance tables
+	AlterSagaInstancesAddDefinitionVersionSQL = `ALTER TABLE %s
+    ADD COLUMN IF NOT EXISTS definition_version int NOT NULL DEFAULT 1`
+
+	CreateSagaHistoryTableSQL = `CREATE TABLE %[1]s (
+    id               bigserial   NOT NULL,
+    saga_name        text        NOT NULL,
+    saga_id          text        NOT NULL,
+    step             int         NOT NULL,
+    compensating     boolean     NOT NULL,
+    end_state        boolean     NOT NULL,
+    event            text        NOT NULL,
+    reply_name       text        NOT NULL,
+    reply_outcome    text        NOT NULL,
+    reply_message_id text        NOT NULL,
+    commands         bytea       NOT NULL,
+    error            text        NOT NULL,
+    created_at       timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+CREATE INDEX %[1]s_saga_idx ON %[1]s (saga_name, saga_id, id)`
+
+	// CreateSagaArchiveTableSQL creates an archive table for a RetentionWorker, like the table it archives
+	CreateSagaArchiveTableSQL = `CREATE TABLE %s (LIKE %s INCLUDING DEFAULTS)`
+
+	CreateOutboxTableSQL = `CREATE TABLE %[1]s (
+    sequence     bigserial   NOT NULL,
+    id           text        NOT NULL UNIQUE,
+    channel      text        NOT NULL,
+    headers      bytea       NOT NULL,
+    payload      bytea       NOT NULL,
+    created_at   timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_at timestamptz,
+    PRIMARY KEY (sequence)
+);
+CREATE INDEX %[1]s_unpublished_idx ON %[1]s (sequence) WHERE published_at IS NULL`
+
+	CreateInboxTableSQL = `CREATE TABLE %[1]s (
+    message_id  text        NOT NULL,
+    receiver    text        NOT NULL,
+    received_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (message_id, receiver)
+);
+CREATE INDEX %[1]s_received_at_idx ON %[1]s (received_at)`
+
+	findSagaInstanceSQL           = "SELECT saga_name, saga_id, saga_data_name, saga_data, current_step, end_state, compensating, deadline, step_deadline, version, modified_at, execution_state, correlation_key, parent_saga_name, parent_saga_id, definition_version FROM %s WHERE saga_name = $1 AND saga_id = $2 LIMIT 1"
+	findOverdueSagaInstancesSQL   = "SELECT saga_name, saga_id, saga_data_name, saga_data, current_step, end_state, compensating, deadline, step_deadline, version, modified_at, execution_state, correlation_key, parent_saga_name, parent_saga_id, definition_version FROM %s WHERE saga_name = $1 AND end_state = false AND (step_deadline < $2 OR (compensating = false AND deadline < $2)) ORDER BY modified_at LIMIT $3"
+	findCorrelatedSagaInstanceSQL = "SELECT saga_name, saga_id, saga_data_name, saga_data, current_step, end_state, compensating, deadline, step_deadline, version, modified_at, execution_state, correlation_key, parent_saga_name, parent_saga_id, definition_version FROM %s WHERE saga_name = $1 AND correlation_key = $2 AND end_state = false ORDER BY modified_at DESC LIMIT 1"
+	findChildSagaInstancesSQL     = "SELECT saga_name, saga_id, saga_data_name, saga_data, current_step, end_state, compensating, deadline, step_deadline, version, modified_at, execution_state, correlation_key, parent_saga_name, parent_saga_id, definition_version FROM %s WHERE parent_saga_name = $1 AND parent_saga_id = $2 ORDER BY modified_at"
+	listSagaInstancesSQL          = "SELECT saga_name, saga_id, saga_data_name, saga_data, current_step, end_state, compensating, deadline, step_deadline, version, modified_at, execution_state, correlation_key, parent_saga_name, parent_saga_id, definition_version FROM %s"
+	saveSagaInstanceSQL           = "INSERT INTO %s (saga_name, saga_id, saga_data_name, saga_data, current_step, end_state, compensating, deadline, step_deadline, version, execution_state, correlation_key, parent_saga_name, parent_saga_id, definition_version, modified_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, CURRENT_TIMESTAMP)"
+	updateSagaInstanceSQL         = "UPDATE %s SET saga_data_name = $1, saga_data = $2, current_step = $3, end_state = $4, compensating = $5, deadline = $6, step_deadline = $7, execution_state = $8, correlation_key = $9, version = version + 1, modified_at = CURRENT_TIMESTAMP WHERE saga_name = $10 AND saga_id = $11 AND version = $12"
+
+	// children are kept until their parent is gone or is an ended root; a running ancestor may still compensate them
+	findExpiredSagaInstancesSQL = "SELECT saga_name, saga_id FROM %[1]s r WHERE end_state = true AND modified_at < $1 AND (parent_saga_id IS NULL OR NOT EXISTS (SELECT 1 FROM %[1]s p WHERE p.saga_name = r.parent_saga_name AND p.saga_id = r.parent_saga_id AND (p.end_state = false OR p.parent_saga_id IS NOT NULL))) ORDER BY modified_at LIMIT $2 FOR UPDATE OF r SKIP LOCKED"
+	findSagaRowsJSONSQL         = "SELECT saga_name, saga_id, row_to_json(r)::text FROM %s r WHERE (saga_name, saga_id) IN (SELECT * FROM unnest($1::text[], $2::text[]))"
+	archiveSagaRowsSQL          = "INSERT INTO %s SELECT * FROM %s WHERE (saga_name, saga_id) IN (SELECT * FROM unnest($1::text[], $2::text[]))"
+	deleteSagaRowsSQL           = "DELETE FROM %s WHERE (saga_name, saga_id) IN (SELECT * FROM unnest($1::text[], $2::text[]))"
+
+	appendSagaHistorySQL = "INSERT INTO %s (saga_name, saga_id, step, compensating, end_state, event, reply_name, reply_outcome, reply_message_id, commands, error, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)"
+	findSagaHistorySQL   = "SELECT saga_name, saga_id, step, compensating, end_state, event, reply_name, reply_outcome, reply_message_id, commands, error, created_at FROM %s WHERE saga_name = $1 AND saga_id = $2 ORDER BY id"
+
+	saveOutboxMessageSQL           = "INSERT INTO %s (id, channel, headers, payload, created_at) VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)"
+	lockOutboxChannelsSQL          = "SELECT channel FROM %[1]s WHERE published_at IS NULL AND sequence IN (SELECT MIN(sequence) FROM %[1]s WHERE published_at IS NULL GROUP BY channel) ORDER BY sequence LIMIT $1 FOR UPDATE SKIP LOCKED"
+	fetchOutboxMessagesSQL         = "SELECT id, channel, headers, payload FROM %s WHERE published_at IS NULL AND channel = ANY($1) ORDER BY sequence LIMIT $2"
+	markOutboxMessagesPublishedSQL = "UPDATE %s SET published_at = CURRENT_TIMESTAMP WHERE id = ANY($1)"
 
-	findSagaInstanceSQL   = "SELECT saga_data_name, saga_data, current_step, end_state, compensating FROM %s WHERE saga_name = $1 AND saga_id = $2 LIMIT 1"
-	saveSagaInstanceSQL   = "INSERT INTO %s (saga_name, saga_id, saga_data_name, saga_data, current_step, end_state, compensating, modified_at) VALUES ($1, $2, $3, $4, $5, $6, $7, CURRENT_TIMESTAMP)"
-	updateSagaInstanceSQL = "UPDATE %s SET saga_data = $1, current_step = $2, end_state = $3, compensating = $4, modified_at = CURRENT_TIMESTAMP WHERE saga_name = $5 AND saga_id = $6"
+	saveInboxMessageSQL   = "INSERT INTO %s (message_id, receiver, received_at) VALUES ($1, $2, CURRENT_TIMESTAMP) ON CONFLICT DO NOTHING"
+	pruneInboxMessagesSQL = "DELETE FROM %[1]s WHERE (message_id, receiver) IN (SELECT message_id, receiver FROM %[1]s WHERE received_at < $1 LIMIT $2)"
 
 	pgxTxKey = contextKey(5432)
 )
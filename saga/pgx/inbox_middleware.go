@@ -0,0 +1,58 @@
+package pgx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+	"go.uber.org/zap"
+)
+
+type inbox struct {
+	tableName string
+	client    Client
+	logger    logger.Logger
+}
+
+// InboxMiddleware records each message ID and receiver name in the inbox table and skips messages already received
+func InboxMiddleware(client Client, logger logger.Logger, options ...InboxOption) func(msg.MessageReceiver) msg.MessageReceiver {
+	i := &inbox{
+		tableName: DefaultInboxTableName,
+		client:    client,
+		logger:    logger,
+	}
+
+	for _, option := range options {
+		option(i)
+	}
+
+	return func(next msg.MessageReceiver) msg.MessageReceiver {
+		named, ok := next.(msg.NamedMessageReceiver)
+		if !ok || named.ReceiverName() == "" {
+			i.logger.Error("the inbox requires a msg.NamedMessageReceiver; name the receiver with msg.NamedReceiver")
+			return msg.ReceiveMessageFunc(func(context.Context, msg.Message) error {
+				return msg.ErrReceiverNotNamed
+			})
+		}
+		receiverName := named.ReceiverName()
+
+		return msg.ReceiveMessageFunc(func(ctx context.Context, message msg.Message) error {
+			tag, err := i.client.Exec(ctx, fmt.Sprintf(saveInboxMessageSQL, i.tableName), message.ID(), receiverName)
+			if err != nil {
+				i.logger.Error("error saving inbox message", zap.String("MessageID", message.ID()), zap.Error(err))
+				return err
+			}
+
+			if tag.RowsAffected() == 0 {
+				i.logger.Info("skipping duplicate message",
+					zap.String("MessageID", message.ID()),
+					zap.String("Receiver", receiverName),
+				)
+				return nil
+			}
+
+			return next.ReceiveMessage(ctx, message)
+		})
+	}
+}
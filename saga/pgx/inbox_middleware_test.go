@@ -0,0 +1,59 @@
+package pgx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+	sagapgx "github.com/nguyenta1993/service-kit/saga/pgx"
+)
+
+func TestInboxMiddleware(t *testing.T) {
+	log := logger.NewDefaultLogger("error")
+
+	receiver := func(received *int) msg.ReceiveMessageFunc {
+		return func(context.Context, msg.Message) error {
+			*received++
+			return nil
+		}
+	}
+
+	tests := map[string]struct {
+		name         string
+		affected     int64
+		wantErr      error
+		wantReceived int
+	}{
+		"Received":  {name: "orders", affected: 1, wantReceived: 1},
+		"Duplicate": {name: "orders", affected: 0},
+		"NotNamed":  {wantErr: msg.ErrReceiverNotNamed},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db := newFakeDB()
+			db.affected["INSERT INTO inbox"] = tt.affected
+
+			received := 0
+			var next msg.MessageReceiver = receiver(&received)
+			if tt.name != "" {
+				next = msg.NamedReceiver(tt.name, next)
+			}
+
+			inbox := sagapgx.InboxMiddleware(db, log)(next)
+
+			err := inbox.ReceiveMessage(context.Background(), msg.NewMessage([]byte("payload")))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReceiveMessage() error = %v, want %v", err, tt.wantErr)
+			}
+			if received != tt.wantReceived {
+				t.Errorf("received %d messages, want %d", received, tt.wantReceived)
+			}
+
+			if saved := db.executed("INSERT INTO inbox"); tt.name != "" && (len(saved) != 1 || saved[0].args[1] != tt.name) {
+				t.Errorf("saved %v, want the message recorded for %s", saved, tt.name)
+			}
+		})
+	}
+}
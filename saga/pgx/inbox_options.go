@@ -0,0 +1,17 @@
+package pgx
+
+import "github.com/nguyenta1993/service-kit/logger"
+
+type InboxOption func(*inbox)
+
+func WithInboxTableName(tableName string) InboxOption {
+	return func(i *inbox) {
+		i.tableName = tableName
+	}
+}
+
+func WithInboxLogger(logger logger.Logger) InboxOption {
+	return func(i *inbox) {
+		i.logger = logger
+	}
+}
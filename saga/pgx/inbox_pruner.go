@@ -0,0 +1,85 @@
+package pgx
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultInboxRetention     = 7 * 24 * time.Hour
+	DefaultInboxPruneInterval = time.Hour
+	DefaultInboxPruneBatch    = 1000
+)
+
+// InboxPruner periodically deletes inbox records older than the retention period
+type InboxPruner struct {
+	tableName string
+	client    Client
+	logger    logger.Logger
+	retention time.Duration
+	interval  time.Duration
+	batchSize int
+}
+
+// NewInboxPruner constructs a new InboxPruner
+func NewInboxPruner(logger logger.Logger, client Client, options ...InboxPrunerOption) *InboxPruner {
+	p := &InboxPruner{
+		tableName: DefaultInboxTableName,
+		client:    client,
+		logger:    logger,
+		retention: DefaultInboxRetention,
+		interval:  DefaultInboxPruneInterval,
+		batchSize: DefaultInboxPruneBatch,
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	return p
+}
+
+// Start prunes the inbox table until the context is cancelled
+func (p *InboxPruner) Start(ctx context.Context) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		pruned, err := p.Prune(ctx)
+		if err != nil {
+			p.logger.Error("error pruning inbox messages", zap.Error(err))
+		} else if pruned > 0 {
+			p.logger.Info("pruned inbox messages", zap.Int64("Count", pruned))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Prune deletes inbox records older than the retention period in batches and returns the number deleted
+func (p *InboxPruner) Prune(ctx context.Context) (int64, error) {
+	var pruned int64
+
+	receivedBefore := time.Now().Add(-p.retention)
+
+	for {
+		tag, err := p.client.Exec(ctx, fmt.Sprintf(pruneInboxMessagesSQL, p.tableName), receivedBefore, p.batchSize)
+		if err != nil {
+			return pruned, err
+		}
+
+		pruned += tag.RowsAffected()
+
+		if tag.RowsAffected() < int64(p.batchSize) || ctx.Err() != nil {
+			return pruned, nil
+		}
+	}
+}
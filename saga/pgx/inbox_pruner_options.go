@@ -0,0 +1,39 @@
+package pgx
+
+import (
+	"time"
+
+	"github.com/nguyenta1993/service-kit/logger"
+)
+
+type InboxPrunerOption func(*InboxPruner)
+
+func WithInboxPrunerTableName(tableName string) InboxPrunerOption {
+	return func(pruner *InboxPruner) {
+		pruner.tableName = tableName
+	}
+}
+
+func WithInboxPrunerRetention(retention time.Duration) InboxPrunerOption {
+	return func(pruner *InboxPruner) {
+		pruner.retention = retention
+	}
+}
+
+func WithInboxPrunerInterval(interval time.Duration) InboxPrunerOption {
+	return func(pruner *InboxPruner) {
+		pruner.interval = interval
+	}
+}
+
+func WithInboxPrunerBatchSize(batchSize int) InboxPrunerOption {
+	return func(pruner *InboxPruner) {
+		pruner.batchSize = batchSize
+	}
+}
+
+func WithInboxPrunerLogger(logger logger.Logger) InboxPrunerOption {
+	return func(pruner *InboxPruner) {
+		pruner.logger = logger
+	}
+}
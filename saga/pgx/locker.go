@@ -0,0 +1,58 @@
+package pgx
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/nguyenta1993/service-kit/saga/saga"
+)
+
+// DefaultSagaTimeoutLockKey is the lock key of a saga.TimeoutScheduler, used with NewAdvisoryLocker
+const DefaultSagaTimeoutLockKey = int64(0x5a6b)
+
+// Locker is the saga.Locker a RetentionWorker takes so only one process removes instances
+type Locker = saga.Locker
+
+// AdvisoryLocker is a Locker that holds a Postgres session advisory lock on a connection of the pool
+type AdvisoryLocker struct {
+	pool *pgxpool.Pool
+	key  int64
+}
+
+var _ Locker = (*AdvisoryLocker)(nil)
+
+// NewAdvisoryLocker constructs a new AdvisoryLocker for the lock key
+func NewAdvisoryLocker(pool *pgxpool.Pool, key int64) *AdvisoryLocker {
+	return &AdvisoryLocker{
+		pool: pool,
+		key:  key,
+	}
+}
+
+// TryLock implements Locker.TryLock
+func (l *AdvisoryLocker) TryLock(ctx context.Context) (func(context.Context) error, bool, error) {
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var locked bool
+	err = conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&locked)
+	if err != nil || !locked {
+		conn.Release()
+		return nil, false, err
+	}
+
+	return func(ctx context.Context) error {
+		defer conn.Release()
+
+		_, err := conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", l.key)
+		if err != nil {
+			// the lock is held until the session ends; do not return the connection to the pool
+			_ = conn.Conn().Close(ctx)
+		}
+
+		return err
+	}, true, nil
+}
@@ -0,0 +1,116 @@
+package pgx
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go.uber.org/zap"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/saga"
+)
+
+const (
+	DefaultNotifierChannel       = "saga_ended"
+	DefaultNotifierRetryInterval = 5 * time.Second
+)
+
+// Notifier is a saga.Notifier that uses Postgres LISTEN/NOTIFY to wake the callers of StartAndWait in every process
+type Notifier struct {
+	channel       string
+	client        Client
+	pool          *pgxpool.Pool
+	logger        logger.Logger
+	retryInterval time.Duration
+	local         *saga.LocalNotifier
+}
+
+type notification struct {
+	SagaName string `json:"saga_name"`
+	SagaID   string `json:"saga_id"`
+}
+
+var _ saga.Notifier = (*Notifier)(nil)
+
+// NewNotifier constructs a new Notifier; the pool provides the connection that listens for notifications
+func NewNotifier(logger logger.Logger, client Client, pool *pgxpool.Pool, options ...NotifierOption) *Notifier {
+	n := &Notifier{
+		channel:       DefaultNotifierChannel,
+		client:        client,
+		pool:          pool,
+		logger:        logger,
+		retryInterval: DefaultNotifierRetryInterval,
+		local:         saga.NewLocalNotifier(),
+	}
+
+	for _, option := range options {
+		option(n)
+	}
+
+	return n
+}
+
+// Notify implements saga.Notifier.Notify
+func (n *Notifier) Notify(ctx context.Context, sagaName, sagaID string) error {
+	payload, err := json.Marshal(notification{SagaName: sagaName, SagaID: sagaID})
+	if err != nil {
+		return err
+	}
+
+	_, err = n.client.Exec(ctx, "SELECT pg_notify($1, $2)", n.channel, string(payload))
+
+	return err
+}
+
+// Listen implements saga.Notifier.Listen
+func (n *Notifier) Listen(sagaName, sagaID string) (<-chan struct{}, func()) {
+	return n.local.Listen(sagaName, sagaID)
+}
+
+// Start listens for notifications until the context is cancelled; the connection is acquired again after errors
+func (n *Notifier) Start(ctx context.Context) error {
+	for {
+		err := n.listen(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		n.logger.Error("error listening for saga notifications", zap.String("Channel", n.channel), zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(n.retryInterval):
+		}
+	}
+}
+
+func (n *Notifier) listen(ctx context.Context) error {
+	conn, err := n.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{n.channel}.Sanitize())
+	if err != nil {
+		return err
+	}
+
+	for {
+		received, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var ended notification
+		if err = json.Unmarshal([]byte(received.Payload), &ended); err != nil {
+			n.logger.Warn("ignoring malformed saga notification", zap.String("Payload", received.Payload), zap.Error(err))
+			continue
+		}
+
+		_ = n.local.Notify(ctx, ended.SagaName, ended.SagaID)
+	}
+}
@@ -0,0 +1,27 @@
+package pgx
+
+import (
+	"time"
+
+	"github.com/nguyenta1993/service-kit/logger"
+)
+
+type NotifierOption func(*Notifier)
+
+func WithNotifierChannel(channel string) NotifierOption {
+	return func(notifier *Notifier) {
+		notifier.channel = channel
+	}
+}
+
+func WithNotifierRetryInterval(interval time.Duration) NotifierOption {
+	return func(notifier *Notifier) {
+		notifier.retryInterval = interval
+	}
+}
+
+func WithNotifierLogger(logger logger.Logger) NotifierOption {
+	return func(notifier *Notifier) {
+		notifier.logger = logger
+	}
+}
@@ -0,0 +1,56 @@
+package pgx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+	"go.uber.org/zap"
+)
+
+// OutboxProducer is a msg.Producer that saves messages into an outbox table with the transaction of the session client
+type OutboxProducer struct {
+	tableName string
+	client    Client
+	logger    logger.Logger
+}
+
+var _ msg.Producer = (*OutboxProducer)(nil)
+
+// NewOutboxProducer constructs a new OutboxProducer
+func NewOutboxProducer(logger logger.Logger, client Client, options ...OutboxProducerOption) *OutboxProducer {
+	p := &OutboxProducer{
+		tableName: DefaultOutboxTableName,
+		client:    client,
+		logger:    logger,
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	return p
+}
+
+// Send implements msg.Producer.Send
+func (p *OutboxProducer) Send(ctx context.Context, channel string, message msg.Message) error {
+	headers, err := json.Marshal(message.Headers())
+	if err != nil {
+		p.logger.Error("error encoding outbox message headers", zap.Error(err))
+		return err
+	}
+
+	_, err = p.client.Exec(ctx, fmt.Sprintf(saveOutboxMessageSQL, p.tableName), message.ID(), channel, headers, message.Payload())
+	if err != nil {
+		p.logger.Error("error saving outbox message", zap.String("MessageID", message.ID()), zap.Error(err))
+	}
+
+	return err
+}
+
+// Close implements msg.Producer.Close
+func (p *OutboxProducer) Close(context.Context) error {
+	return nil
+}
@@ -0,0 +1,265 @@
+package pgx_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+	sagapgx "github.com/nguyenta1993/service-kit/saga/pgx"
+)
+
+// statement is a query or exec recorded by a fakeDB
+type statement struct {
+	sql  string
+	args []interface{}
+}
+
+// fakeDB is a Client and the pgx.Tx it begins; queries are answered with the rows queued by the test for the first
+// fragment found in the SQL, and every statement is recorded
+type fakeDB struct {
+	pgx.Tx
+
+	mu         sync.Mutex
+	rows       map[string][][][]interface{}
+	affected   map[string]int64
+	errs       map[string]error
+	statements []statement
+	open       bool
+	commits    int
+	rollbacks  int
+}
+
+var _ sagapgx.Client = (*fakeDB)(nil)
+
+func newFakeDB() *fakeDB {
+	return &fakeDB{
+		rows:     map[string][][][]interface{}{},
+		affected: map[string]int64{},
+		errs:     map[string]error{},
+	}
+}
+
+// queueRows sets the rows returned by the next query containing the fragment
+func (db *fakeDB) queueRows(fragment string, rows ...[]interface{}) {
+	db.rows[fragment] = append(db.rows[fragment], rows)
+}
+
+// executed returns the statements containing the fragment
+func (db *fakeDB) executed(fragment string) []statement {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	var found []statement
+	for _, s := range db.statements {
+		if strings.Contains(s.sql, fragment) {
+			found = append(found, s)
+		}
+	}
+
+	return found
+}
+
+func (db *fakeDB) record(sql string, args []interface{}) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.statements = append(db.statements, statement{sql: sql, args: args})
+	for fragment, err := range db.errs {
+		if strings.Contains(sql, fragment) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *fakeDB) Exec(_ context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	if err := db.record(sql, args); err != nil {
+		return nil, err
+	}
+
+	var affected int64
+	for fragment, n := range db.affected {
+		if strings.Contains(sql, fragment) {
+			affected = n
+		}
+	}
+
+	return pgconn.CommandTag(fmt.Sprintf("UPDATE %d", affected)), nil
+}
+
+func (db *fakeDB) Query(_ context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	if err := db.record(sql, args); err != nil {
+		return nil, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for fragment, queued := range db.rows {
+		if strings.Contains(sql, fragment) && len(queued) > 0 {
+			db.rows[fragment] = queued[1:]
+			return &fakeRows{rows: queued[0]}, nil
+		}
+	}
+
+	return &fakeRows{}, nil
+}
+
+func (db *fakeDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return fakeRow{err: err}
+	}
+
+	return fakeRow{rows: rows.(*fakeRows)}
+}
+
+func (db *fakeDB) QueryFunc(context.Context, string, []interface{}, []interface{}, func(pgx.QueryFuncRow) error) (pgconn.CommandTag, error) {
+	return nil, errors.New("QueryFunc is not supported by fakeDB")
+}
+
+func (db *fakeDB) SendBatch(context.Context, *pgx.Batch) pgx.BatchResults {
+	panic("SendBatch is not supported by fakeDB")
+}
+
+func (db *fakeDB) Begin(context.Context) (pgx.Tx, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.open = true
+
+	return db, nil
+}
+
+func (db *fakeDB) BeginTx(ctx context.Context, _ pgx.TxOptions) (pgx.Tx, error) {
+	return db.Begin(ctx)
+}
+
+func (db *fakeDB) Commit(context.Context) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.open = false
+	db.commits++
+
+	return nil
+}
+
+// Rollback is a no-op once the transaction is committed, as it is with pgx
+func (db *fakeDB) Rollback(context.Context) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.open {
+		db.open = false
+		db.rollbacks++
+	}
+
+	return nil
+}
+
+type fakeRows struct {
+	pgx.Rows
+	rows [][]interface{}
+	row  []interface{}
+}
+
+func (r *fakeRows) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	r.row, r.rows = r.rows[0], r.rows[1:]
+
+	return true
+}
+
+func (r *fakeRows) Scan(dest ...interface{}) error {
+	if len(dest) != len(r.row) {
+		return fmt.Errorf("scanning %d values into %d destinations", len(r.row), len(dest))
+	}
+
+	for i, value := range r.row {
+		if value == nil {
+			continue
+		}
+		reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(value))
+	}
+
+	return nil
+}
+
+func (r *fakeRows) Err() error { return nil }
+func (r *fakeRows) Close()     {}
+
+type fakeRow struct {
+	rows *fakeRows
+	err  error
+}
+
+func (r fakeRow) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	if !r.rows.Next() {
+		return pgx.ErrNoRows
+	}
+
+	return r.rows.Scan(dest...)
+}
+
+func TestOutboxProducer_Send(t *testing.T) {
+	log := logger.NewDefaultLogger("error")
+
+	tests := map[string]struct {
+		withTx  bool
+		wantErr error
+	}{
+		"Saved":         {withTx: true},
+		"NoTransaction": {wantErr: sagapgx.ErrTxNotInContext},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			db := newFakeDB()
+			if tt.withTx {
+				ctx = sagapgx.WithTx(ctx, db)
+			}
+
+			producer := sagapgx.NewOutboxProducer(log, sagapgx.NewSessionClient(), sagapgx.WithOutboxProducerTableName("outbox"))
+			message := msg.NewMessage([]byte("payload"), msg.WithHeaders(msg.Headers{"KEY": "value"}))
+
+			if err := producer.Send(ctx, "inventory", message); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Send() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			saved := db.executed("INSERT INTO outbox")
+			if len(saved) != 1 {
+				t.Fatalf("saved %d outbox messages, want 1", len(saved))
+			}
+
+			args := saved[0].args
+			if args[0] != message.ID() || args[1] != "inventory" || string(args[3].([]byte)) != "payload" {
+				t.Errorf("saved id, channel, payload = %v, %v, %s", args[0], args[1], args[3])
+			}
+
+			var headers msg.Headers
+			if err := json.Unmarshal(args[2].([]byte), &headers); err != nil || headers.Get("KEY") != "value" {
+				t.Errorf("saved headers = %s, error = %v", args[2], err)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package pgx
+
+import (
+	"time"
+
+	"github.com/nguyenta1993/service-kit/logger"
+)
+
+type OutboxRelayOption func(*OutboxRelay)
+
+func WithOutboxRelayTableName(tableName string) OutboxRelayOption {
+	return func(relay *OutboxRelay) {
+		relay.tableName = tableName
+	}
+}
+
+func WithOutboxRelayBatchSize(batchSize int) OutboxRelayOption {
+	return func(relay *OutboxRelay) {
+		relay.batchSize = batchSize
+	}
+}
+
+func WithOutboxRelayPollInterval(pollInterval time.Duration) OutboxRelayOption {
+	return func(relay *OutboxRelay) {
+		relay.pollInterval = pollInterval
+	}
+}
+
+func WithOutboxRelayLogger(logger logger.Logger) OutboxRelayOption {
+	return func(relay *OutboxRelay) {
+		relay.logger = logger
+	}
+}
@@ -0,0 +1,275 @@
+package pgx_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+	sagapgx "github.com/nguyenta1993/service-kit/saga/pgx"
+)
+
+// failingProducer records the messages sent and fails every send to the failing channel
+type failingProducer struct {
+	mu      sync.Mutex
+	failing string
+	sent    []string
+}
+
+func (p *failingProducer) Send(_ context.Context, channel string, message msg.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if channel == p.failing {
+		return errors.New("broker unavailable")
+	}
+	p.sent = append(p.sent, message.ID())
+
+	return nil
+}
+
+func (p *failingProducer) Close(context.Context) error { return nil }
+
+func TestOutboxRelay_Start(t *testing.T) {
+	log := logger.NewDefaultLogger("error")
+
+	db := newFakeDB()
+	headers := []byte(`{}`)
+	db.queueRows("SKIP LOCKED", []interface{}{"orders"}, []interface{}{"inventory"})
+	db.queueRows("ANY($1)",
+		[]interface{}{"1", "orders", headers, []byte("a")},
+		[]interface{}{"2", "inventory", headers, []byte("b")},
+		[]interface{}{"3", "orders", headers, []byte("c")},
+		[]interface{}{"4", "inventory", headers, []byte("d")},
+	)
+	producer := &failingProducer{failing: "inventory"}
+
+	relay := sagapgx.NewOutboxRelay(log, db, producer,
+		sagapgx.WithOutboxRelayTableName("outbox"),
+		sagapgx.WithOutboxRelayPollInterval(time.Hour),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- relay.Start(ctx) }()
+
+	deadline := time.Now().Add(time.Second)
+	for len(db.executed("UPDATE outbox")) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	fetched := db.executed("ANY($1)")
+	if len(fetched) == 0 || !reflect.DeepEqual(fetched[0].args[0], []string{"orders", "inventory"}) {
+		t.Fatalf("fetched with %v, want the messages of the locked channels", fetched)
+	}
+
+	if want := []string{"1", "3"}; !reflect.DeepEqual(producer.sent, want) {
+		t.Errorf("sent %v, want %v", producer.sent, want)
+	}
+
+	marked := db.executed("UPDATE outbox")
+	if len(marked) != 1 || !reflect.DeepEqual(marked[0].args[0], []string{"1", "3"}) {
+		t.Errorf("marked published %v, want [1 3]", marked)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.commits != 1 {
+		t.Errorf("commits = %d, want 1", db.commits)
+	}
+}
+
+// outboxTable is a Client holding outbox messages; each transaction locks the channels it selects with SKIP LOCKED
+// until it ends, and the messages it marks published are published when it commits
+type outboxTable struct {
+	sagapgx.Client
+
+	mu        sync.Mutex
+	messages  []outboxRow
+	locks     map[string]*outboxTx
+	published map[string]bool
+}
+
+type outboxRow struct {
+	id      string
+	channel string
+}
+
+type outboxTx struct {
+	pgx.Tx
+
+	table   *outboxTable
+	channel []string
+	marked  []string
+}
+
+func (t *outboxTable) Begin(context.Context) (pgx.Tx, error) {
+	return &outboxTx{table: t}, nil
+}
+
+func (tx *outboxTx) Query(_ context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	t := tx.table
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var rows [][]interface{}
+	if strings.Contains(sql, "SKIP LOCKED") {
+		heads := map[string]bool{}
+		for _, m := range t.messages {
+			if t.published[m.id] || heads[m.channel] {
+				continue
+			}
+			heads[m.channel] = true
+
+			if t.locks[m.channel] == nil && len(rows) < args[0].(int) {
+				t.locks[m.channel] = tx
+				tx.channel = append(tx.channel, m.channel)
+				rows = append(rows, []interface{}{m.channel})
+			}
+		}
+
+		return &fakeRows{rows: rows}, nil
+	}
+
+	channels := args[0].([]string)
+	for _, m := range t.messages {
+		for _, channel := range channels {
+			if m.channel == channel && !t.published[m.id] && len(rows) < args[1].(int) {
+				rows = append(rows, []interface{}{m.id, m.channel, []byte(`{}`), []byte(m.id)})
+			}
+		}
+	}
+
+	return &fakeRows{rows: rows}, nil
+}
+
+func (tx *outboxTx) Exec(_ context.Context, _ string, args ...interface{}) (pgconn.CommandTag, error) {
+	tx.marked = append(tx.marked, args[0].([]string)...)
+
+	return pgconn.CommandTag("UPDATE"), nil
+}
+
+func (tx *outboxTx) Commit(ctx context.Context) error {
+	tx.table.mu.Lock()
+	for _, id := range tx.marked {
+		tx.table.published[id] = true
+	}
+	tx.table.mu.Unlock()
+
+	return tx.Rollback(ctx)
+}
+
+func (tx *outboxTx) Rollback(context.Context) error {
+	tx.table.mu.Lock()
+	defer tx.table.mu.Unlock()
+
+	for _, channel := range tx.channel {
+		delete(tx.table.locks, channel)
+	}
+	tx.channel, tx.marked = nil, nil
+
+	return nil
+}
+
+// orderedProducer records the messages sent by channel; the first send to orders waits until a send to inventory
+// has started, so it only returns in time when two relays deliver at once
+type orderedProducer struct {
+	mu        sync.Mutex
+	sent      map[string][]string
+	inventory chan struct{}
+	once      sync.Once
+	waited    bool
+	timedOut  bool
+}
+
+func (p *orderedProducer) Send(_ context.Context, channel string, message msg.Message) error {
+	if channel == "inventory" {
+		p.once.Do(func() { close(p.inventory) })
+	}
+
+	p.mu.Lock()
+	first := channel == "orders" && !p.waited
+	p.waited = p.waited || first
+	p.mu.Unlock()
+
+	if first {
+		select {
+		case <-p.inventory:
+		case <-time.After(time.Second):
+			p.mu.Lock()
+			p.timedOut = true
+			p.mu.Unlock()
+			return errors.New("inventory not delivered")
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sent[channel] = append(p.sent[channel], message.ID())
+
+	return nil
+}
+
+func (p *orderedProducer) Close(context.Context) error { return nil }
+
+func TestOutboxRelay_Concurrent(t *testing.T) {
+	log := logger.NewDefaultLogger("error")
+
+	table := &outboxTable{locks: map[string]*outboxTx{}, published: map[string]bool{}}
+	want := map[string][]string{}
+	for i := 0; i < 20; i++ {
+		channel := []string{"orders", "inventory"}[i%2]
+		id := fmt.Sprintf("%s-%d", channel, i)
+		table.messages = append(table.messages, outboxRow{id: id, channel: channel})
+		want[channel] = append(want[channel], id)
+	}
+	producer := &orderedProducer{sent: map[string][]string{}, inventory: make(chan struct{})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		relay := sagapgx.NewOutboxRelay(log, table, producer,
+			sagapgx.WithOutboxRelayBatchSize(1),
+			sagapgx.WithOutboxRelayPollInterval(time.Millisecond),
+		)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = relay.Start(ctx)
+		}()
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		table.mu.Lock()
+		done := len(table.published) == len(table.messages)
+		table.mu.Unlock()
+		if done {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	wg.Wait()
+
+	if producer.timedOut {
+		t.Errorf("inventory was not delivered while orders was being delivered, want the relays to deliver at once")
+	}
+
+	if !reflect.DeepEqual(producer.sent, want) {
+		t.Errorf("sent %v, want each channel delivered once in order %v", producer.sent, want)
+	}
+}
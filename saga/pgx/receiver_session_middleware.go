@@ -23,7 +23,7 @@ func ReceiverSessionMiddleware(conn *pgxpool.Pool, logger logger.Logger) func(ms
 				return fmt.Errorf("failed to start transaction: %s", err.Error())
 			}
 
-			txCtx := context.WithValue(ctx, pgxTxKey, tx)
+			txCtx := WithTx(ctx, tx)
 
 			defer func() {
 				p := recover()
@@ -0,0 +1,73 @@
+package pgx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/saga"
+)
+
+type SagaHistoryStore struct {
+	tableName string
+	client    Client
+	logger    logger.Logger
+}
+
+var _ saga.HistoryStore = (*SagaHistoryStore)(nil)
+
+// NewSagaHistoryStore constructs a new SagaHistoryStore
+func NewSagaHistoryStore(logger logger.Logger, client Client, options ...SagaHistoryStoreOption) *SagaHistoryStore {
+	s := &SagaHistoryStore{
+		tableName: DefaultSagaHistoryTableName,
+		client:    client,
+		logger:    logger,
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+func (s *SagaHistoryStore) Append(ctx context.Context, record saga.HistoryRecord) error {
+	commands, err := json.Marshal(record.Commands)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Exec(ctx, fmt.Sprintf(appendSagaHistorySQL, s.tableName), record.SagaName, record.SagaID, record.Step, record.Compensating, record.EndState, string(record.Event), record.ReplyName, record.ReplyOutcome, record.ReplyMessageID, commands, record.Error, record.CreatedAt)
+	return err
+}
+
+func (s *SagaHistoryStore) Timeline(ctx context.Context, sagaName, sagaID string) ([]saga.HistoryRecord, error) {
+	rows, err := s.client.Query(ctx, fmt.Sprintf(findSagaHistorySQL, s.tableName), sagaName, sagaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []saga.HistoryRecord
+
+	for rows.Next() {
+		var record saga.HistoryRecord
+		var event string
+		var commands []byte
+
+		err = rows.Scan(&record.SagaName, &record.SagaID, &record.Step, &record.Compensating, &record.EndState, &event, &record.ReplyName, &record.ReplyOutcome, &record.ReplyMessageID, &commands, &record.Error, &record.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		record.Event = saga.HistoryEvent(event)
+		if err = json.Unmarshal(commands, &record.Commands); err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
@@ -0,0 +1,17 @@
+package pgx
+
+import "github.com/nguyenta1993/service-kit/logger"
+
+type SagaHistoryStoreOption func(*SagaHistoryStore)
+
+func WithSagaHistoryStoreTableName(tableName string) SagaHistoryStoreOption {
+	return func(store *SagaHistoryStore) {
+		store.tableName = tableName
+	}
+}
+
+func WithSagaHistoryStoreLogger(logger logger.Logger) SagaHistoryStoreOption {
+	return func(store *SagaHistoryStore) {
+		store.logger = logger
+	}
+}
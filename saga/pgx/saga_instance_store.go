@@ -2,11 +2,17 @@ package pgx
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/nguyenta1993/service-kit/logger"
 	"github.com/nguyenta1993/service-kit/saga/core"
 	"github.com/nguyenta1993/service-kit/saga/saga"
+
+	"github.com/jackc/pgx/v4"
 )
 
 type SagaInstanceStore struct {
@@ -16,7 +22,12 @@ type SagaInstanceStore struct {
 }
 
 var _ saga.InstanceStore = (*SagaInstanceStore)(nil)
+var _ saga.OverdueFinder = (*SagaInstanceStore)(nil)
+var _ saga.InstanceLister = (*SagaInstanceStore)(nil)
+var _ saga.CorrelationKeyFinder = (*SagaInstanceStore)(nil)
+var _ saga.ChildFinder = (*SagaInstanceStore)(nil)
 
+// NewSagaInstanceStore constructs a new SagaInstanceStore
 func NewSagaInstanceStore(logger logger.Logger, client Client, options ...SagaInstanceStoreOption) *SagaInstanceStore {
 	s := &SagaInstanceStore{
 		tableName: DefaultSagaInstanceTableName,
@@ -32,23 +43,124 @@ func NewSagaInstanceStore(logger logger.Logger, client Client, options ...SagaIn
 }
 
 func (s *SagaInstanceStore) Find(ctx context.Context, sagaName, sagaID string) (*saga.Instance, error) {
-	var dataName string
-	var data []byte
-	var currentStep int
-	var endState, compensating bool
-
 	row := s.client.QueryRow(ctx, fmt.Sprintf(findSagaInstanceSQL, s.tableName), sagaName, sagaID)
-	err := row.Scan(&dataName, &data, &currentStep, &endState, &compensating)
+
+	instance, err := s.scan(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, saga.ErrInstanceNotFound
+	}
+
+	return instance, err
+}
+
+func (s *SagaInstanceStore) FindByCorrelationKey(ctx context.Context, sagaName, correlationKey string) (*saga.Instance, error) {
+	row := s.client.QueryRow(ctx, fmt.Sprintf(findCorrelatedSagaInstanceSQL, s.tableName), sagaName, correlationKey)
+
+	instance, err := s.scan(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, saga.ErrInstanceNotFound
+	}
+
+	return instance, err
+}
+
+func (s *SagaInstanceStore) FindChildren(ctx context.Context, parentSagaName, parentSagaID string) ([]*saga.Instance, error) {
+	rows, err := s.client.Query(ctx, fmt.Sprintf(findChildSagaInstancesSQL, s.tableName), parentSagaName, parentSagaID)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	sagaData, err := core.DeserializeSagaData(dataName, data)
+	var instances []*saga.Instance
+
+	for rows.Next() {
+		instance, err := s.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, instance)
+	}
+
+	return instances, rows.Err()
+}
+
+func (s *SagaInstanceStore) FindOverdue(ctx context.Context, sagaName string, now time.Time, limit int) ([]*saga.Instance, error) {
+	rows, err := s.client.Query(ctx, fmt.Sprintf(findOverdueSagaInstancesSQL, s.tableName), sagaName, now, limit)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
+
+	var instances []*saga.Instance
+
+	for rows.Next() {
+		instance, err := s.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, instance)
+	}
+
+	return instances, rows.Err()
+}
+
+func (s *SagaInstanceStore) List(ctx context.Context, query saga.InstanceQuery) ([]*saga.Instance, error) {
+	var conditions []string
+	var args []interface{}
+
+	if query.SagaName != "" {
+		args = append(args, query.SagaName)
+		conditions = append(conditions, fmt.Sprintf("saga_name = $%d", len(args)))
+	}
+
+	switch query.State {
+	case saga.InstanceRunning:
+		conditions = append(conditions, "end_state = false AND compensating = false")
+	case saga.InstanceCompensating:
+		conditions = append(conditions, "end_state = false AND compensating = true")
+	case saga.InstanceEnded:
+		conditions = append(conditions, "end_state = true")
+	}
+
+	if !query.ModifiedBefore.IsZero() {
+		args = append(args, query.ModifiedBefore)
+		conditions = append(conditions, fmt.Sprintf("modified_at < $%d", len(args)))
+	}
+
+	if query.DefinitionVersion != 0 {
+		args = append(args, query.DefinitionVersion)
+		conditions = append(conditions, fmt.Sprintf("definition_version = $%d", len(args)))
+	}
+
+	sql := fmt.Sprintf(listSagaInstancesSQL, s.tableName)
+	if len(conditions) > 0 {
+		sql += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultSagaInstanceListLimit
+	}
+	args = append(args, limit, query.Offset)
+	sql += fmt.Sprintf(" ORDER BY modified_at DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := s.client.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var instances []*saga.Instance
+
+	for rows.Next() {
+		instance, err := s.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, instance)
+	}
 
-	return saga.NewSagaInstance(sagaName, sagaID, sagaData, currentStep, endState, compensating), nil
+	return instances, rows.Err()
 }
 
 func (s *SagaInstanceStore) Save(ctx context.Context, sagaInstance *saga.Instance) error {
@@ -56,7 +168,11 @@ func (s *SagaInstanceStore) Save(ctx context.Context, sagaInstance *saga.Instanc
 	if err != nil {
 		return err
 	}
-	_, err = s.client.Exec(ctx, fmt.Sprintf(saveSagaInstanceSQL, s.tableName), sagaInstance.SagaName(), sagaInstance.SagaID(), sagaInstance.SagaData().SagaDataName(), data, sagaInstance.CurrentStep(), sagaInstance.EndState(), sagaInstance.Compensating())
+	state, err := json.Marshal(sagaInstance.ExecutionState())
+	if err != nil {
+		return err
+	}
+	_, err = s.client.Exec(ctx, fmt.Sprintf(saveSagaInstanceSQL, s.tableName), sagaInstance.SagaName(), sagaInstance.SagaID(), core.VersionedSagaDataName(sagaInstance.SagaData()), data, sagaInstance.CurrentStep(), sagaInstance.EndState(), sagaInstance.Compensating(), nullTime(sagaInstance.Deadline()), nullTime(sagaInstance.StepDeadline()), sagaInstance.Version(), state, nullString(sagaInstance.CorrelationKey()), nullString(sagaInstance.ParentSagaName()), nullString(sagaInstance.ParentSagaID()), sagaInstance.DefinitionVersion())
 	return err
 }
 
@@ -65,6 +181,93 @@ func (s *SagaInstanceStore) Update(ctx context.Context, sagaInstance *saga.Insta
 	if err != nil {
 		return err
 	}
-	_, err = s.client.Exec(ctx, fmt.Sprintf(updateSagaInstanceSQL, s.tableName), data, sagaInstance.CurrentStep(), sagaInstance.EndState(), sagaInstance.Compensating(), sagaInstance.SagaName(), sagaInstance.SagaID())
-	return err
+	state, err := json.Marshal(sagaInstance.ExecutionState())
+	if err != nil {
+		return err
+	}
+	tag, err := s.client.Exec(ctx, fmt.Sprintf(updateSagaInstanceSQL, s.tableName), core.VersionedSagaDataName(sagaInstance.SagaData()), data, sagaInstance.CurrentStep(), sagaInstance.EndState(), sagaInstance.Compensating(), nullTime(sagaInstance.Deadline()), nullTime(sagaInstance.StepDeadline()), state, nullString(sagaInstance.CorrelationKey()), sagaInstance.SagaName(), sagaInstance.SagaID(), sagaInstance.Version())
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return &saga.ErrInstanceConflict{
+			SagaName: sagaInstance.SagaName(),
+			SagaID:   sagaInstance.SagaID(),
+			Version:  sagaInstance.Version(),
+		}
+	}
+
+	return nil
+}
+
+func (s *SagaInstanceStore) scan(row interface{ Scan(...interface{}) error }) (*saga.Instance, error) {
+	var sagaName, sagaID, dataName string
+	var data, state []byte
+	var currentStep int
+	var endState, compensating bool
+	var deadline, stepDeadline *time.Time
+	var version, definitionVersion int
+	var modifiedAt time.Time
+	var correlationKey, parentSagaName, parentSagaID *string
+
+	err := row.Scan(&sagaName, &sagaID, &dataName, &data, &currentStep, &endState, &compensating, &deadline, &stepDeadline, &version, &modifiedAt, &state, &correlationKey, &parentSagaName, &parentSagaID, &definitionVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	var executionState saga.ExecutionState
+	if len(state) > 0 {
+		if err = json.Unmarshal(state, &executionState); err != nil {
+			return nil, err
+		}
+	}
+
+	sagaData, err := core.DeserializeSagaData(dataName, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return saga.NewSagaInstance(sagaName, sagaID, sagaData, currentStep, endState, compensating,
+		saga.WithInstanceDeadline(timeValue(deadline)),
+		saga.WithInstanceStepDeadline(timeValue(stepDeadline)),
+		saga.WithInstanceVersion(version),
+		saga.WithInstanceModifiedAt(modifiedAt),
+		saga.WithInstanceExecutionState(executionState),
+		saga.WithInstanceCorrelationKey(stringValue(correlationKey)),
+		saga.WithInstanceParent(stringValue(parentSagaName), stringValue(parentSagaID)),
+		saga.WithInstanceDefinitionVersion(definitionVersion),
+	), nil
+}
+
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
+
+func timeValue(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+
+	return *t
+}
+
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
 }
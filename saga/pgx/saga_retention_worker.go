@@ -0,0 +1,340 @@
+package pgx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/nguyenta1993/service-kit/logger"
+)
+
+const (
+	DefaultSagaRetention         = 30 * 24 * time.Hour
+	DefaultSagaRetentionInterval = time.Hour
+	DefaultSagaRetentionBatch    = 500
+	DefaultSagaRetentionLockKey  = int64(0x5a6a) // used with NewAdvisoryLocker
+)
+
+// RetentionWorker periodically removes, archives or writes out the ended saga instances older than the retention period
+type RetentionWorker struct {
+	tableName               string
+	historyTableName        string
+	archiveTableName        string
+	historyArchiveTableName string
+	archive                 io.Writer
+	archiveMu               sync.Mutex
+	client                  Client
+	locker                  Locker
+	logger                  logger.Logger
+	retention               time.Duration
+	interval                time.Duration
+	batchSize               int
+	registerer              prometheus.Registerer
+	purged                  *prometheus.CounterVec
+}
+
+// archivedInstance is a line of the archive file
+type archivedInstance struct {
+	Instance json.RawMessage   `json:"instance"`
+	History  []json.RawMessage `json:"history,omitempty"`
+}
+
+// NewRetentionWorker constructs a new RetentionWorker; the client must not be a session client
+func NewRetentionWorker(logger logger.Logger, client Client, options ...RetentionWorkerOption) *RetentionWorker {
+	w := &RetentionWorker{
+		tableName: DefaultSagaInstanceTableName,
+		client:    client,
+		logger:    logger,
+		retention: DefaultSagaRetention,
+		interval:  DefaultSagaRetentionInterval,
+		batchSize: DefaultSagaRetentionBatch,
+	}
+
+	for _, option := range options {
+		option(w)
+	}
+
+	if w.registerer != nil {
+		w.purged = w.registerPurged()
+	}
+
+	return w
+}
+
+// registerPurged registers the purged rows counter, or returns the one registered by another worker
+func (w *RetentionWorker) registerPurged() *prometheus.CounterVec {
+	purged := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "saga_retention_purged_rows_total",
+		Help: "Saga instance and history rows removed by the retention worker",
+	}, []string{"table"})
+
+	err := w.registerer.Register(purged)
+	if err == nil {
+		return purged
+	}
+
+	var registered prometheus.AlreadyRegisteredError
+	if errors.As(err, &registered) {
+		if existing, ok := registered.ExistingCollector.(*prometheus.CounterVec); ok {
+			return existing
+		}
+	}
+
+	w.logger.Error("error registering the saga retention metrics", zap.Error(err))
+
+	return nil
+}
+
+// Start removes expired saga instances until the context is cancelled
+func (w *RetentionWorker) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := w.Purge(ctx)
+		if err != nil {
+			w.logger.Error("error purging saga instances", zap.Error(err))
+		} else if purged > 0 {
+			w.logger.Info("purged saga instances", zap.Int64("Count", purged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge removes the expired saga instances in batches, stopping once another process holds the lock
+func (w *RetentionWorker) Purge(ctx context.Context) (int64, error) {
+	var purged int64
+
+	modifiedBefore := time.Now().Add(-w.retention)
+
+	for {
+		count, locked, err := w.lockedBatch(ctx, modifiedBefore)
+		purged += count
+		if err != nil {
+			return purged, err
+		}
+
+		if !locked {
+			w.logger.Debug("saga instances are being purged by another process")
+			return purged, nil
+		}
+
+		if count < int64(w.batchSize) || ctx.Err() != nil {
+			return purged, nil
+		}
+	}
+}
+
+// lockedBatch removes a batch while holding the lock; a lock expiring after a long purge is not shared by two batches
+func (w *RetentionWorker) lockedBatch(ctx context.Context, modifiedBefore time.Time) (int64, bool, error) {
+	if w.locker == nil {
+		count, err := w.purgeBatch(ctx, modifiedBefore)
+		return count, true, err
+	}
+
+	unlock, locked, err := w.locker.TryLock(ctx)
+	if err != nil || !locked {
+		return 0, false, err
+	}
+	defer func() {
+		if err := unlock(context.Background()); err != nil {
+			w.logger.Error("error releasing the saga retention lock", zap.Error(err))
+		}
+	}()
+
+	count, err := w.purgeBatch(ctx, modifiedBefore)
+
+	return count, true, err
+}
+
+// purgeBatch removes a batch in a transaction, writing the archive file before the commit
+func (w *RetentionWorker) purgeBatch(ctx context.Context, modifiedBefore time.Time) (purged int64, err error) {
+	tx, err := w.client.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			if rerr := tx.Rollback(ctx); rerr != nil {
+				w.logger.Error("error rolling back saga retention batch", zap.Error(rerr))
+			}
+		}
+	}()
+
+	sagaNames, sagaIDs, err := w.findExpired(ctx, tx, modifiedBefore)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(sagaIDs) == 0 {
+		return 0, tx.Rollback(ctx)
+	}
+
+	var archived []byte
+	if w.archive != nil {
+		if archived, err = w.archiveLines(ctx, tx, sagaNames, sagaIDs); err != nil {
+			return 0, err
+		}
+	}
+
+	var historyPurged int64
+	if w.historyTableName != "" {
+		historyPurged, err = w.move(ctx, tx, w.historyTableName, w.historyArchiveTableName, sagaNames, sagaIDs)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	purged, err = w.move(ctx, tx, w.tableName, w.archiveTableName, sagaNames, sagaIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(archived) > 0 {
+		if err = w.writeArchive(archived); err != nil {
+			w.logger.Error("error writing the saga archive file; the batch is kept", zap.Int64("Count", purged), zap.Error(err))
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	if w.purged != nil {
+		w.purged.WithLabelValues(w.tableName).Add(float64(purged))
+		if w.historyTableName != "" {
+			w.purged.WithLabelValues(w.historyTableName).Add(float64(historyPurged))
+		}
+	}
+
+	return purged, nil
+}
+
+// writeArchive writes the lines to the archive file, and flushes and syncs it when the writer supports it
+func (w *RetentionWorker) writeArchive(lines []byte) error {
+	w.archiveMu.Lock()
+	defer w.archiveMu.Unlock()
+
+	if _, err := w.archive.Write(lines); err != nil {
+		return err
+	}
+
+	if flusher, ok := w.archive.(interface{ Flush() error }); ok {
+		if err := flusher.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if syncer, ok := w.archive.(interface{ Sync() error }); ok {
+		return syncer.Sync()
+	}
+
+	return nil
+}
+
+func (w *RetentionWorker) findExpired(ctx context.Context, tx pgx.Tx, modifiedBefore time.Time) ([]string, []string, error) {
+	rows, err := tx.Query(ctx, fmt.Sprintf(findExpiredSagaInstancesSQL, w.tableName), modifiedBefore, w.batchSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var sagaNames, sagaIDs []string
+	for rows.Next() {
+		var sagaName, sagaID string
+		if err = rows.Scan(&sagaName, &sagaID); err != nil {
+			return nil, nil, err
+		}
+		sagaNames = append(sagaNames, sagaName)
+		sagaIDs = append(sagaIDs, sagaID)
+	}
+
+	return sagaNames, sagaIDs, rows.Err()
+}
+
+// move deletes the rows of the instances from the table, copying them to the archive table first when it is set
+func (w *RetentionWorker) move(ctx context.Context, tx pgx.Tx, tableName, archiveTableName string, sagaNames, sagaIDs []string) (int64, error) {
+	if archiveTableName != "" {
+		if _, err := tx.Exec(ctx, fmt.Sprintf(archiveSagaRowsSQL, archiveTableName, tableName), sagaNames, sagaIDs); err != nil {
+			return 0, err
+		}
+	}
+
+	tag, err := tx.Exec(ctx, fmt.Sprintf(deleteSagaRowsSQL, tableName), sagaNames, sagaIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// archiveLines returns a JSON line with the instance and history rows of each instance
+func (w *RetentionWorker) archiveLines(ctx context.Context, tx pgx.Tx, sagaNames, sagaIDs []string) ([]byte, error) {
+	instances, order, err := w.findRowsJSON(ctx, tx, w.tableName, sagaNames, sagaIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var history map[string][]json.RawMessage
+	if w.historyTableName != "" {
+		if history, _, err = w.findRowsJSON(ctx, tx, w.historyTableName, sagaNames, sagaIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	var lines bytes.Buffer
+	encoder := json.NewEncoder(&lines)
+	for _, key := range order {
+		if err = encoder.Encode(archivedInstance{Instance: instances[key][0], History: history[key]}); err != nil {
+			return nil, err
+		}
+	}
+
+	return lines.Bytes(), nil
+}
+
+// findRowsJSON returns the rows of the instances as JSON by instance, and the instances in the order first found
+func (w *RetentionWorker) findRowsJSON(ctx context.Context, tx pgx.Tx, tableName string, sagaNames, sagaIDs []string) (map[string][]json.RawMessage, []string, error) {
+	query := fmt.Sprintf(findSagaRowsJSONSQL, tableName)
+	if tableName == w.historyTableName {
+		query += " ORDER BY id"
+	}
+
+	rows, err := tx.Query(ctx, query, sagaNames, sagaIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	found := map[string][]json.RawMessage{}
+	var order []string
+	for rows.Next() {
+		var sagaName, sagaID, row string
+		if err = rows.Scan(&sagaName, &sagaID, &row); err != nil {
+			return nil, nil, err
+		}
+
+		key := sagaName + "/" + sagaID
+		if _, exists := found[key]; !exists {
+			order = append(order, key)
+		}
+		found[key] = append(found[key], json.RawMessage(row))
+	}
+
+	return found, order, rows.Err()
+}
@@ -0,0 +1,78 @@
+package pgx
+
+import (
+	"io"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/nguyenta1993/service-kit/logger"
+)
+
+type RetentionWorkerOption func(*RetentionWorker)
+
+func WithRetentionWorkerTableName(tableName string) RetentionWorkerOption {
+	return func(worker *RetentionWorker) {
+		worker.tableName = tableName
+	}
+}
+
+// WithRetentionWorkerHistoryTableName is an option to remove the history of the instances from the history table
+func WithRetentionWorkerHistoryTableName(tableName string) RetentionWorkerOption {
+	return func(worker *RetentionWorker) {
+		worker.historyTableName = tableName
+	}
+}
+
+// WithRetentionWorkerArchiveTables is an option to move the rows to archive tables, see CreateSagaArchiveTableSQL
+func WithRetentionWorkerArchiveTables(tableName, historyTableName string) RetentionWorkerOption {
+	return func(worker *RetentionWorker) {
+		worker.archiveTableName = tableName
+		worker.historyArchiveTableName = historyTableName
+	}
+}
+
+// WithRetentionWorkerArchiveFile is an option to write the rows to the writer as JSON lines before each commit
+func WithRetentionWorkerArchiveFile(archive io.Writer) RetentionWorkerOption {
+	return func(worker *RetentionWorker) {
+		worker.archive = archive
+	}
+}
+
+// WithRetentionWorkerLocker is an option to only remove instances while holding the lock, e.g. an AdvisoryLocker
+func WithRetentionWorkerLocker(locker Locker) RetentionWorkerOption {
+	return func(worker *RetentionWorker) {
+		worker.locker = locker
+	}
+}
+
+func WithRetentionWorkerRetention(retention time.Duration) RetentionWorkerOption {
+	return func(worker *RetentionWorker) {
+		worker.retention = retention
+	}
+}
+
+func WithRetentionWorkerInterval(interval time.Duration) RetentionWorkerOption {
+	return func(worker *RetentionWorker) {
+		worker.interval = interval
+	}
+}
+
+func WithRetentionWorkerBatchSize(batchSize int) RetentionWorkerOption {
+	return func(worker *RetentionWorker) {
+		worker.batchSize = batchSize
+	}
+}
+
+// WithRetentionWorkerMetrics is an option to register the count of removed rows with the registerer
+func WithRetentionWorkerMetrics(registerer prometheus.Registerer) RetentionWorkerOption {
+	return func(worker *RetentionWorker) {
+		worker.registerer = registerer
+	}
+}
+
+func WithRetentionWorkerLogger(logger logger.Logger) RetentionWorkerOption {
+	return func(worker *RetentionWorker) {
+		worker.logger = logger
+	}
+}
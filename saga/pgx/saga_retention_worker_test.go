@@ -0,0 +1,140 @@
+package pgx_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	sagapgx "github.com/nguyenta1993/service-kit/saga/pgx"
+)
+
+// archiveWriter records the archived lines and the commits the fakeDB had made when they were written
+type archiveWriter struct {
+	db      *fakeDB
+	err     error
+	lines   []string
+	commits []int
+}
+
+func (w *archiveWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	w.db.mu.Lock()
+	w.commits = append(w.commits, w.db.commits)
+	w.db.mu.Unlock()
+	w.lines = append(w.lines, string(p))
+
+	return len(p), nil
+}
+
+func TestRetentionWorker_Purge(t *testing.T) {
+	log := logger.NewDefaultLogger("error")
+	errWrite := errors.New("disk full")
+
+	tests := map[string]struct {
+		writeErr      error
+		wantPurged    int64
+		wantCommits   int
+		wantRollbacks int
+	}{
+		"ArchivedBeforeCommit": {wantPurged: 1, wantCommits: 1},
+		"WriteFailed":          {writeErr: errWrite, wantRollbacks: 1},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db := newFakeDB()
+			db.queueRows("end_state = true", []interface{}{"order", "1"})
+			db.queueRows("row_to_json", []interface{}{"order", "1", `{"saga_id":"1"}`})
+			db.affected["DELETE"] = 1
+
+			archive := &archiveWriter{db: db, err: tt.writeErr}
+			worker := sagapgx.NewRetentionWorker(log, db, sagapgx.WithRetentionWorkerArchiveFile(archive))
+
+			purged, err := worker.Purge(context.Background())
+			if !errors.Is(err, tt.writeErr) {
+				t.Fatalf("Purge() error = %v, want %v", err, tt.writeErr)
+			}
+			if purged != tt.wantPurged {
+				t.Errorf("Purge() = %d, want %d", purged, tt.wantPurged)
+			}
+
+			db.mu.Lock()
+			commits, rollbacks := db.commits, db.rollbacks
+			db.mu.Unlock()
+			if commits != tt.wantCommits || rollbacks != tt.wantRollbacks {
+				t.Errorf("commits, rollbacks = %d, %d, want %d, %d", commits, rollbacks, tt.wantCommits, tt.wantRollbacks)
+			}
+
+			if tt.writeErr != nil {
+				return
+			}
+			if len(archive.lines) != 1 || !strings.Contains(archive.lines[0], `"saga_id":"1"`) || archive.commits[0] != 0 {
+				t.Errorf("archived %v after %v commits, want the instance before the commit", archive.lines, archive.commits)
+			}
+		})
+	}
+}
+
+func TestRetentionWorker_ExpiredChildren(t *testing.T) {
+	db := newFakeDB()
+	worker := sagapgx.NewRetentionWorker(logger.NewDefaultLogger("error"), db)
+
+	if _, err := worker.Purge(context.Background()); err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+
+	// a child is only expired once its parent is gone or is an ended root that can no longer compensate it
+	found := db.executed("end_state = true")
+	if len(found) != 1 {
+		t.Fatalf("executed %d expired instance queries, want 1", len(found))
+	}
+	for _, fragment := range []string{
+		"parent_saga_id IS NULL OR NOT EXISTS",
+		"p.saga_name = r.parent_saga_name AND p.saga_id = r.parent_saga_id",
+		"p.end_state = false OR p.parent_saga_id IS NOT NULL",
+	} {
+		if !strings.Contains(found[0].sql, fragment) {
+			t.Errorf("expired instance query %q does not contain %q", found[0].sql, fragment)
+		}
+	}
+}
+
+func TestRetentionWorker_SharedRegisterer(t *testing.T) {
+	log := logger.NewDefaultLogger("error")
+	registry := prometheus.NewRegistry()
+
+	for i := 0; i < 2; i++ {
+		db := newFakeDB()
+		db.queueRows("end_state = true", []interface{}{"order", "1"})
+		db.affected["DELETE"] = 1
+
+		worker := sagapgx.NewRetentionWorker(log, db, sagapgx.WithRetentionWorkerMetrics(registry))
+		if _, err := worker.Purge(context.Background()); err != nil {
+			t.Fatalf("Purge() error = %v", err)
+		}
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got float64
+	for _, family := range families {
+		if family.GetName() != "saga_retention_purged_rows_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			got += metric.GetCounter().GetValue()
+		}
+	}
+	if got != 2 {
+		t.Errorf("saga_retention_purged_rows_total = %v, want 2", got)
+	}
+}
@@ -0,0 +1,48 @@
+package pgx
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go.uber.org/zap"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/saga"
+)
+
+// NewSession returns a saga.Session that runs in a transaction, or a savepoint of the transaction in the context
+func NewSession(conn *pgxpool.Pool, logger logger.Logger) saga.Session {
+	return func(ctx context.Context, fn func(context.Context) error) (err error) {
+		var tx pgx.Tx
+
+		if outer, txErr := (sessionClient{}).tx(ctx); txErr == nil {
+			tx, err = outer.Begin(ctx)
+		} else {
+			tx, err = conn.Begin(ctx)
+		}
+		if err != nil {
+			logger.Error("error while starting the saga session transaction", zap.Error(err))
+			return err
+		}
+
+		defer func() {
+			p := recover()
+			switch {
+			case p != nil:
+				if txErr := tx.Rollback(ctx); txErr != nil {
+					logger.Error("error while rolling back the saga session transaction during panic", zap.Error(txErr))
+				}
+				panic(p)
+			case err != nil:
+				if txErr := tx.Rollback(ctx); txErr != nil {
+					logger.Error("error while rolling back the saga session transaction", zap.Error(txErr))
+				}
+			default:
+				err = tx.Commit(ctx)
+			}
+		}()
+
+		return fn(WithTx(ctx, tx))
+	}
+}
@@ -0,0 +1,14 @@
+package redis
+
+import (
+	"time"
+)
+
+const (
+	DefaultInboxKeyPrefix = "inbox:"
+	DefaultInboxRetention = 7 * 24 * time.Hour
+
+	DefaultNotifierChannel = "saga_ended"
+
+	DefaultLockerTTL = 30 * time.Minute
+)
@@ -0,0 +1,71 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+	"go.uber.org/zap"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type inbox struct {
+	client    redis.UniversalClient
+	logger    logger.Logger
+	keyPrefix string
+	retention time.Duration
+}
+
+// InboxMiddleware records each message ID and receiver name in Redis for the retention period and skips messages already received
+func InboxMiddleware(client redis.UniversalClient, logger logger.Logger, options ...InboxOption) func(msg.MessageReceiver) msg.MessageReceiver {
+	i := &inbox{
+		client:    client,
+		logger:    logger,
+		keyPrefix: DefaultInboxKeyPrefix,
+		retention: DefaultInboxRetention,
+	}
+
+	for _, option := range options {
+		option(i)
+	}
+
+	return func(next msg.MessageReceiver) msg.MessageReceiver {
+		named, ok := next.(msg.NamedMessageReceiver)
+		if !ok || named.ReceiverName() == "" {
+			i.logger.Error("the inbox requires a msg.NamedMessageReceiver; name the receiver with msg.NamedReceiver")
+			return msg.ReceiveMessageFunc(func(context.Context, msg.Message) error {
+				return msg.ErrReceiverNotNamed
+			})
+		}
+		receiverName := named.ReceiverName()
+
+		return msg.ReceiveMessageFunc(func(ctx context.Context, message msg.Message) error {
+			key := i.keyPrefix + receiverName + ":" + message.ID()
+
+			saved, err := i.client.SetNX(ctx, key, 1, i.retention).Result()
+			if err != nil {
+				i.logger.Error("error saving inbox message", zap.String("MessageID", message.ID()), zap.Error(err))
+				return err
+			}
+
+			if !saved {
+				i.logger.Info("skipping duplicate message",
+					zap.String("MessageID", message.ID()),
+					zap.String("Receiver", receiverName),
+				)
+				return nil
+			}
+
+			err = next.ReceiveMessage(ctx, message)
+			if err != nil {
+				if delErr := i.client.Del(ctx, key).Err(); delErr != nil {
+					i.logger.Error("error removing inbox message", zap.String("MessageID", message.ID()), zap.Error(delErr))
+				}
+			}
+
+			return err
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package redis
+
+import (
+	"time"
+
+	"github.com/nguyenta1993/service-kit/logger"
+)
+
+type InboxOption func(*inbox)
+
+func WithInboxKeyPrefix(keyPrefix string) InboxOption {
+	return func(i *inbox) {
+		i.keyPrefix = keyPrefix
+	}
+}
+
+func WithInboxRetention(retention time.Duration) InboxOption {
+	return func(i *inbox) {
+		i.retention = retention
+	}
+}
+
+func WithInboxLogger(logger logger.Logger) InboxOption {
+	return func(i *inbox) {
+		i.logger = logger
+	}
+}
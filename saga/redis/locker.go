@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/nguyenta1993/service-kit/saga/saga"
+)
+
+// Locker is a saga.Locker that holds an expiring lock in Redis
+type Locker struct {
+	client redis.UniversalClient
+	key    string
+	ttl    time.Duration
+}
+
+var _ saga.Locker = (*Locker)(nil)
+
+// unlockScript deletes the lock only while it is still held with the token
+var unlockScript = redis.NewScript(`if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// NewLocker constructs a new Locker for the key
+func NewLocker(client redis.UniversalClient, key string, options ...LockerOption) *Locker {
+	l := &Locker{
+		client: client,
+		key:    key,
+		ttl:    DefaultLockerTTL,
+	}
+
+	for _, option := range options {
+		option(l)
+	}
+
+	return l
+}
+
+// TryLock returns whether the lock was acquired and, when it was, the function that releases it
+func (l *Locker) TryLock(ctx context.Context) (func(context.Context) error, bool, error) {
+	token := uuid.New().String()
+
+	locked, err := l.client.SetNX(ctx, l.key, token, l.ttl).Result()
+	if err != nil || !locked {
+		return nil, false, err
+	}
+
+	return func(ctx context.Context) error {
+		return unlockScript.Run(ctx, l.client, []string{l.key}, token).Err()
+	}, true, nil
+}
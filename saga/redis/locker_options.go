@@ -0,0 +1,13 @@
+package redis
+
+import (
+	"time"
+)
+
+type LockerOption func(*Locker)
+
+func WithLockerTTL(ttl time.Duration) LockerOption {
+	return func(l *Locker) {
+		l.ttl = ttl
+	}
+}
@@ -0,0 +1,85 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/saga"
+)
+
+// Notifier is a saga.Notifier that uses Redis pub/sub to wake the callers of StartAndWait in every process
+type Notifier struct {
+	client  redis.UniversalClient
+	logger  logger.Logger
+	channel string
+	local   *saga.LocalNotifier
+}
+
+type notification struct {
+	SagaName string `json:"saga_name"`
+	SagaID   string `json:"saga_id"`
+}
+
+var _ saga.Notifier = (*Notifier)(nil)
+
+// NewNotifier constructs a new Notifier
+func NewNotifier(client redis.UniversalClient, logger logger.Logger, options ...NotifierOption) *Notifier {
+	n := &Notifier{
+		client:  client,
+		logger:  logger,
+		channel: DefaultNotifierChannel,
+		local:   saga.NewLocalNotifier(),
+	}
+
+	for _, option := range options {
+		option(n)
+	}
+
+	return n
+}
+
+// Notify implements saga.Notifier.Notify
+func (n *Notifier) Notify(ctx context.Context, sagaName, sagaID string) error {
+	payload, err := json.Marshal(notification{SagaName: sagaName, SagaID: sagaID})
+	if err != nil {
+		return err
+	}
+
+	return n.client.Publish(ctx, n.channel, payload).Err()
+}
+
+// Listen implements saga.Notifier.Listen
+func (n *Notifier) Listen(sagaName, sagaID string) (<-chan struct{}, func()) {
+	return n.local.Listen(sagaName, sagaID)
+}
+
+// Start subscribes to notifications until the context is cancelled; the client resubscribes after connection errors
+func (n *Notifier) Start(ctx context.Context) error {
+	pubsub := n.client.Subscribe(ctx, n.channel)
+	defer pubsub.Close()
+
+	received := pubsub.Channel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case message, ok := <-received:
+			if !ok {
+				return nil
+			}
+
+			var ended notification
+			if err := json.Unmarshal([]byte(message.Payload), &ended); err != nil {
+				n.logger.Warn("ignoring malformed saga notification", zap.String("Payload", message.Payload), zap.Error(err))
+				continue
+			}
+
+			_ = n.local.Notify(ctx, ended.SagaName, ended.SagaID)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package redis
+
+import (
+	"github.com/nguyenta1993/service-kit/logger"
+)
+
+type NotifierOption func(*Notifier)
+
+func WithNotifierChannel(channel string) NotifierOption {
+	return func(n *Notifier) {
+		n.channel = channel
+	}
+}
+
+func WithNotifierLogger(logger logger.Logger) NotifierOption {
+	return func(n *Notifier) {
+		n.logger = logger
+	}
+}
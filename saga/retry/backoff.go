@@ -66,7 +66,7 @@ func (b Backoff) Retry(ctx context.Context, fn func() error) error {
 			return fmt.Errorf("%v: %w", MaxRetriesExceeded, err)
 		}
 
-		if b.maxElapsed != 0 && started.Add(b.maxElapsed).After(time.Now()) {
+		if b.maxElapsed != 0 && time.Since(started) >= b.maxElapsed {
 			return fmt.Errorf("%v: %w", MaxElapsedExceeded, err)
 		}
 
@@ -89,6 +89,29 @@ func (b Backoff) Retry(ctx context.Context, fn func() error) error {
 	}
 }
 
+// Delay returns the interval to wait after a number of failed attempts; false is returned once the retries are exhausted
+func (b Backoff) Delay(attempts int, elapsed time.Duration) (time.Duration, bool) {
+	if b.maxRetries != 0 && attempts >= b.maxRetries {
+		return 0, false
+	}
+
+	if b.maxElapsed != 0 && elapsed >= b.maxElapsed {
+		return 0, false
+	}
+
+	return b.Interval(attempts), true
+}
+
+// Interval returns the interval to wait after a number of failed attempts without applying any limits
+func (b Backoff) Interval(attempts int) time.Duration {
+	interval := b.initialInterval
+	for i := 1; i < attempts; i++ {
+		interval = b.nextInterval(interval)
+	}
+
+	return interval
+}
+
 func (b Backoff) nextInterval(lastInterval time.Duration) time.Duration {
 	// Either there isn't any delay or there is not growth
 	if b.initialInterval == 0 || lastInterval == b.maxInterval {
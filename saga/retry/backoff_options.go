@@ -21,6 +21,13 @@ func WithBackoffMaxRetries(maxRetries int) BackoffOption {
 	}
 }
 
+// WithBackoffMaxElapsed sets the maximum time allowed from the first failure for Backoff
+func WithBackoffMaxElapsed(maxElapsed time.Duration) BackoffOption {
+	return func(backoff *Backoff) {
+		backoff.maxElapsed = maxElapsed
+	}
+}
+
 // WithBackoffMaxInterval sets the maximum interval duration for Backoff
 func WithBackoffMaxInterval(maxInterval time.Duration) BackoffOption {
 	return func(backoff *Backoff) {
@@ -0,0 +1,189 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nguyenta1993/service-kit/saga/core"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+)
+
+// AwaitStep is used to pause a saga until an external event with its correlation key arrives
+type AwaitStep struct {
+	event          core.Event
+	correlationKey func(core.SagaData) string
+	eventKey       func(msg.Event) string
+	handler        func(context.Context, core.SagaData, core.Event) error
+	timeout        time.Duration
+}
+
+var _ Step = (*AwaitStep)(nil)
+
+// NewAwaitStep constructor for AwaitStep
+func NewAwaitStep(event core.Event, correlationKey func(core.SagaData) string, eventKey func(msg.Event) string) AwaitStep {
+	return AwaitStep{
+		event:          event,
+		correlationKey: correlationKey,
+		eventKey:       eventKey,
+	}
+}
+
+// Handle sets the handler that is called with the event; an error compensates the saga
+func (s AwaitStep) Handle(handler func(context.Context, core.SagaData, core.Event) error) AwaitStep {
+	s.handler = handler
+	return s
+}
+
+// Timeout sets how long the step waits for the event before it is treated as a failure
+func (s AwaitStep) Timeout(timeout time.Duration) AwaitStep {
+	s.timeout = timeout
+	return s
+}
+
+// EventName returns the name of the event the step waits for
+func (s AwaitStep) EventName() string {
+	return s.event.EventName()
+}
+
+func (s AwaitStep) hasInvocableAction(_ context.Context, _ core.SagaData, compensating bool) bool {
+	return !compensating
+}
+
+func (s AwaitStep) getReplyHandler(string, bool) func(context.Context, core.SagaData, core.Reply) error {
+	return nil
+}
+
+func (s AwaitStep) execute(_ context.Context, sagaData core.SagaData, _ bool) func(results *stepResults) {
+	key := s.correlationKey(sagaData)
+	return func(results *stepResults) {
+		results.correlationKey = key
+		results.timeout = s.timeout
+	}
+}
+
+func (s AwaitStep) validate() error {
+	if s.event == nil || s.correlationKey == nil || s.eventKey == nil {
+		return fmt.Errorf("await step requires an event, a correlation key and an event key")
+	}
+
+	if !core.IsRegistered(s.event.EventName()) {
+		return fmt.Errorf("event `%s` is awaited but has not been registered with core.RegisterEvents", s.event.EventName())
+	}
+
+	return nil
+}
+
+func (s AwaitStep) kind() stepKind {
+	return compensatableStep
+}
+
+// HandleEvents registers the events awaited by the AwaitSteps of the saga with the dispatcher
+func (o *Orchestrator) HandleEvents(dispatcher *msg.EventDispatcher) {
+	if _, ok := o.instanceStore.(CorrelationKeyFinder); !ok {
+		o.logger.Error("not handling awaited events; the instance store is not a saga.CorrelationKeyFinder", zap.String("SagaName", o.definition.SagaName()))
+		return
+	}
+
+	handled := map[string]bool{}
+
+	for _, v := range o.versions {
+		for _, step := range v.definition.Steps() {
+			if awaitStep, ok := step.(AwaitStep); ok && !handled[awaitStep.EventName()] {
+				dispatcher.Handle(awaitStep.event, o.ReceiveEvent)
+				handled[awaitStep.EventName()] = true
+			}
+		}
+	}
+}
+
+// ReceiveEvent resumes the instance waiting for the event in an AwaitStep; events no instance waits for are ignored
+func (o *Orchestrator) ReceiveEvent(ctx context.Context, event msg.Event) error {
+	for _, v := range o.versions {
+		for i, step := range v.definition.Steps() {
+			awaitStep, ok := step.(AwaitStep)
+			if !ok || awaitStep.EventName() != event.Event().EventName() {
+				continue
+			}
+
+			key := awaitStep.eventKey(event)
+			if key == "" {
+				continue
+			}
+
+			v, stepIndex := v, i
+			err := o.retryConflicts(ctx, func(ctx context.Context) error {
+				return v.processEvent(ctx, stepIndex, awaitStep, key, event)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (o *Orchestrator) processEvent(ctx context.Context, stepIndex int, step AwaitStep, key string, event msg.Event) error {
+	logger := o.logger.With(
+		zap.String("SagaName", o.definition.SagaName()),
+		zap.String("EventName", step.EventName()),
+		zap.String("CorrelationKey", key),
+	)
+
+	finder, ok := o.instanceStore.(CorrelationKeyFinder)
+	if !ok {
+		return fmt.Errorf("finding instances by correlation key: %w", ErrStoreNotSupported)
+	}
+
+	instance, err := finder.FindByCorrelationKey(ctx, o.definition.SagaName(), key)
+	if errors.Is(err, ErrInstanceNotFound) {
+		logger.Debug("no saga instance is waiting for the event")
+		return nil
+	}
+	if err != nil {
+		logger.Error("failed to locate saga instance data", zap.Error(err))
+		return err
+	}
+
+	logger = logger.With(zap.String("SagaID", instance.sagaID))
+
+	if instance.DefinitionVersion() != DefinitionVersion(o.definition) {
+		logger.Debug("saga instance is executed by another definition version")
+		return nil
+	}
+
+	stepCtx := instance.getStepContext()
+	if stepCtx.step != stepIndex || stepCtx.compensating || stepCtx.ended {
+		logger.Info("saga instance is not waiting for the event")
+		return nil
+	}
+
+	cause := historyCause{event: HistoryEventReceived}
+	outcome := msg.WithSuccess()
+
+	if step.handler != nil {
+		if err = step.handler(ctx, instance.SagaData(), event.Event()); err != nil {
+			logger.Warn("await step handler returned an error; treating it as a failure", zap.Error(err))
+			cause.err = err
+			outcome = msg.WithFailure()
+		}
+	}
+
+	results, err := o.handleReply(ctx, stepCtx, instance.SagaData(), outcome)
+	if err != nil {
+		logger.Error("error handling the event outcome", zap.Error(err))
+		return err
+	}
+
+	err = o.processResults(ctx, instance, results, cause)
+	if err != nil {
+		logger.Error("error while processing results", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
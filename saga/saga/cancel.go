@@ -0,0 +1,54 @@
+package saga
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+)
+
+// Cancel stops an instance; it is compensated, starting with the current step, once the current step replies
+func (o *Orchestrator) Cancel(ctx context.Context, sagaID, reason string) error {
+	return o.retryConflicts(ctx, func(ctx context.Context) error {
+		v, instance, err := o.findRunning(ctx, sagaID)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case instance.compensating:
+			return ErrSagaCompensating
+		case instance.executionState.Cancelled:
+			return ErrSagaCancelled
+		case v.pastPivot(instance.currentStep):
+			return ErrSagaPastPivot
+		}
+
+		cause := historyCause{event: HistoryCancelled}
+		if reason != "" {
+			cause.err = errors.New(reason)
+		}
+
+		instance.executionState.CancelReason = reason
+		stepCtx := instance.getStepContext()
+		stepCtx.cancelled = true
+
+		err = v.processResults(ctx, instance, &stepResults{updatedStepContext: stepCtx, waiting: true}, cause)
+		if err != nil {
+			return err
+		}
+
+		afterSession(ctx, func(context.Context) {
+			v.logger.Info("executing saga cancelled hook", zap.String("SagaName", v.definition.SagaName()), zap.String("SagaID", sagaID))
+			v.definition.OnHook(SagaCancelled, instance)
+		})
+
+		_, awaiting := v.definition.Steps()[stepCtx.step].(AwaitStep)
+		if !stepCtx.retrying && !awaiting {
+			return nil
+		}
+
+		// the current step has failed already or waits for an event; there is no reply to wait for
+		return v.processResults(ctx, instance, v.executeNextStep(ctx, stepCtx.compensate(), instance.SagaData()), historyCause{event: HistoryCancelled})
+	})
+}
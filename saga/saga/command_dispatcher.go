@@ -7,6 +7,10 @@ import (
 	"github.com/nguyenta1993/service-kit/logger"
 	"github.com/nguyenta1993/service-kit/saga/core"
 	"github.com/nguyenta1993/service-kit/saga/msg"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -71,7 +75,7 @@ func (d *CommandDispatcher) ReceiveMessage(ctx context.Context, message msg.Mess
 
 	logger.Info("saga command handler found")
 
-	command, err := core.DeserializeCommand(commandName, message.Payload())
+	command, err := core.DeserializeCommand(core.VersionedName(commandName, msg.SchemaVersion(message.Headers())), message.Payload())
 	if err != nil {
 		logger.Error("error decoding saga command message payload", zap.Error(err))
 		return nil
@@ -85,11 +89,22 @@ func (d *CommandDispatcher) ReceiveMessage(ctx context.Context, message msg.Mess
 
 	correlationHeaders := d.correlationHeaders(message.Headers())
 
+	// the handler joins the trace of the saga and the replies carry the trace of the handler
+	ctx = otel.GetTextMapPropagator().Extract(ctx, headersCarrier{message.Headers(), MessageCommandTracePrefix})
+	ctx, span := tracer.Start(ctx, "saga command "+commandName, trace.WithSpanKind(trace.SpanKindConsumer), trace.WithAttributes(
+		attribute.String("saga.name", sagaName),
+		attribute.String("saga.id", sagaID),
+	))
+	defer span.End()
+
 	cmdMsg := commandMessage{sagaID, sagaName, command, correlationHeaders}
 
 	replies, err := handler(ctx, cmdMsg)
+	otel.GetTextMapPropagator().Inject(ctx, headersCarrier{correlationHeaders, MessageReplyTracePrefix})
 	if err != nil {
 		logger.Error("saga command handler returned an error", zap.Error(err))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		rerr := d.sendReplies(ctx, replyChannel, []msg.Reply{msg.WithFailure()}, correlationHeaders)
 		if rerr != nil {
 			logger.Error("error sending replies", zap.Error(rerr))
@@ -149,7 +164,7 @@ func (d *CommandDispatcher) sendReplies(ctx context.Context, replyChannel string
 func (d *CommandDispatcher) correlationHeaders(headers msg.Headers) msg.Headers {
 	replyHeaders := make(map[string]string)
 	for key, value := range headers {
-		if key == msg.MessageCommandName {
+		if key == msg.MessageCommandName || strings.HasPrefix(key, MessageCommandTracePrefix) {
 			continue
 		}
 
@@ -15,14 +15,32 @@ const (
 	SagaStarting LifecycleHook = iota
 	SagaCompleted
 	SagaCompensated
+	SagaCancelled
 )
 
 // Saga message headers
 const (
-	MessageCommandSagaID   = msg.MessageCommandPrefix + "SAGA_ID"
-	MessageCommandSagaName = msg.MessageCommandPrefix + "SAGA_NAME"
-	MessageCommandResource = msg.MessageCommandPrefix + "RESOURCE"
+	MessageCommandSagaID     = msg.MessageCommandPrefix + "SAGA_ID"
+	MessageCommandSagaName   = msg.MessageCommandPrefix + "SAGA_NAME"
+	MessageCommandResource   = msg.MessageCommandPrefix + "RESOURCE"
+	MessageCommandSagaBranch = msg.MessageCommandPrefix + "SAGA_BRANCH"
+	// MessageCommandSagaStep and MessageCommandSagaCompensating are the step and direction the command was sent for
+	MessageCommandSagaStep         = msg.MessageCommandPrefix + "SAGA_STEP"
+	MessageCommandSagaCompensating = msg.MessageCommandPrefix + "SAGA_COMPENSATING"
+	// MessageCommandSagaSequence numbers the sends of the instance so replies to an earlier send can be dropped
+	MessageCommandSagaSequence = msg.MessageCommandPrefix + "SAGA_SEQUENCE"
+	// MessageCommandTracePrefix prefixes the trace context headers of commands
+	MessageCommandTracePrefix = msg.MessageCommandTracePrefix
 
-	MessageReplySagaID   = msg.MessageReplyPrefix + "SAGA_ID"
-	MessageReplySagaName = msg.MessageReplyPrefix + "SAGA_NAME"
+	MessageReplySagaID      = msg.MessageReplyPrefix + "SAGA_ID"
+	MessageReplySagaName    = msg.MessageReplyPrefix + "SAGA_NAME"
+	MessageReplySagaTimeout = msg.MessageReplyPrefix + "SAGA_TIMEOUT"
+	MessageReplySagaBranch  = msg.MessageReplyPrefix + "SAGA_BRANCH"
+	// MessageReplySagaStep and MessageReplySagaCompensating echo the step and direction of the command
+	MessageReplySagaStep         = msg.MessageReplyPrefix + "SAGA_STEP"
+	MessageReplySagaCompensating = msg.MessageReplyPrefix + "SAGA_COMPENSATING"
+	// MessageReplySagaSequence echoes MessageCommandSagaSequence
+	MessageReplySagaSequence = msg.MessageReplyPrefix + "SAGA_SEQUENCE"
+	// MessageReplyTracePrefix prefixes the trace context headers of replies
+	MessageReplyTracePrefix = msg.MessageReplyTracePrefix
 )
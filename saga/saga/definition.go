@@ -7,3 +7,18 @@ type Definition interface {
 	Steps() []Step
 	OnHook(hook LifecycleHook, instance *Instance)
 }
+
+// VersionedDefinition is a Definition with a version; instances are executed by the version that started them
+type VersionedDefinition interface {
+	Definition
+	Version() int
+}
+
+// DefinitionVersion returns the version of the definition; definitions without a version are version 1
+func DefinitionVersion(definition Definition) int {
+	if versioned, ok := definition.(VersionedDefinition); ok && versioned.Version() > 1 {
+		return versioned.Version()
+	}
+
+	return 1
+}
@@ -0,0 +1,169 @@
+package saga
+
+import (
+	"fmt"
+)
+
+// DefinitionBuilder builds a validated Definition
+type DefinitionBuilder struct {
+	sagaName     string
+	replyChannel string
+	version      int
+	steps        []Step
+	hooks        map[LifecycleHook][]func(instance *Instance)
+}
+
+type definition struct {
+	sagaName     string
+	replyChannel string
+	version      int
+	steps        []Step
+	hooks        map[LifecycleHook][]func(instance *Instance)
+}
+
+var _ VersionedDefinition = (*definition)(nil)
+
+// NewDefinition starts building a Definition for the saga that receives replies on the reply channel
+func NewDefinition(sagaName, replyChannel string) *DefinitionBuilder {
+	return &DefinitionBuilder{
+		sagaName:     sagaName,
+		replyChannel: replyChannel,
+		version:      1,
+		hooks:        map[LifecycleHook][]func(instance *Instance){},
+	}
+}
+
+// Version sets the version of the definition; it defaults to 1
+func (b *DefinitionBuilder) Version(version int) *DefinitionBuilder {
+	b.version = version
+
+	return b
+}
+
+// Step adds one or more steps to the definition
+func (b *DefinitionBuilder) Step(steps ...Step) *DefinitionBuilder {
+	b.steps = append(b.steps, steps...)
+
+	return b
+}
+
+// OnStarting adds a function that is called when a saga is starting
+func (b *DefinitionBuilder) OnStarting(fn func(instance *Instance)) *DefinitionBuilder {
+	return b.onHook(SagaStarting, fn)
+}
+
+// OnCompleted adds a function that is called when a saga has completed all of its steps
+func (b *DefinitionBuilder) OnCompleted(fn func(instance *Instance)) *DefinitionBuilder {
+	return b.onHook(SagaCompleted, fn)
+}
+
+// OnCompensated adds a function that is called when a saga has been compensated
+func (b *DefinitionBuilder) OnCompensated(fn func(instance *Instance)) *DefinitionBuilder {
+	return b.onHook(SagaCompensated, fn)
+}
+
+// OnCancelled adds a function that is called when a saga has been cancelled, before it is compensated
+func (b *DefinitionBuilder) OnCancelled(fn func(instance *Instance)) *DefinitionBuilder {
+	return b.onHook(SagaCancelled, fn)
+}
+
+// Build validates the steps and returns the Definition
+func (b *DefinitionBuilder) Build() (Definition, error) {
+	if b.sagaName == "" {
+		return nil, fmt.Errorf("saga name cannot be blank")
+	}
+
+	if b.replyChannel == "" {
+		return nil, fmt.Errorf("saga `%s` reply channel cannot be blank", b.sagaName)
+	}
+
+	if b.version < 1 {
+		return nil, fmt.Errorf("saga `%s` version must be 1 or greater, got %d", b.sagaName, b.version)
+	}
+
+	if len(b.steps) == 0 {
+		return nil, fmt.Errorf("saga `%s` has no steps", b.sagaName)
+	}
+
+	for i, step := range b.steps {
+		if err := step.validate(); err != nil {
+			return nil, fmt.Errorf("saga `%s` step %d: %w", b.sagaName, i, err)
+		}
+	}
+
+	if err := b.validateOrder(); err != nil {
+		return nil, fmt.Errorf("saga `%s` %w", b.sagaName, err)
+	}
+
+	hooks := make(map[LifecycleHook][]func(instance *Instance), len(b.hooks))
+	for hook, fns := range b.hooks {
+		hooks[hook] = append([]func(instance *Instance){}, fns...)
+	}
+
+	return &definition{
+		sagaName:     b.sagaName,
+		replyChannel: b.replyChannel,
+		version:      b.version,
+		steps:        append([]Step{}, b.steps...),
+		hooks:        hooks,
+	}, nil
+}
+
+// validateOrder checks the steps are compensatable steps, then at most one pivot, then retriable steps
+func (b *DefinitionBuilder) validateOrder() error {
+	pivot, retriable := -1, -1
+
+	for i, step := range b.steps {
+		switch step.kind() {
+		case pivotStep:
+			if pivot != -1 {
+				return fmt.Errorf("step %d: a saga has one pivot step; step %d is the pivot", i, pivot)
+			}
+			if retriable != -1 {
+				return fmt.Errorf("step %d: the pivot step follows retriable step %d", i, retriable)
+			}
+			pivot = i
+		case retriableStep:
+			if retriable == -1 {
+				retriable = i
+			}
+		default:
+			if pivot != -1 {
+				return fmt.Errorf("step %d: a compensatable step follows pivot step %d", i, pivot)
+			}
+			if retriable != -1 {
+				return fmt.Errorf("step %d: a compensatable step follows retriable step %d", i, retriable)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (b *DefinitionBuilder) onHook(hook LifecycleHook, fn func(instance *Instance)) *DefinitionBuilder {
+	b.hooks[hook] = append(b.hooks[hook], fn)
+
+	return b
+}
+
+func (d *definition) SagaName() string {
+	return d.sagaName
+}
+
+func (d *definition) ReplyChannel() string {
+	return d.replyChannel
+}
+
+func (d *definition) Version() int {
+	return d.version
+}
+
+func (d *definition) Steps() []Step {
+	return d.steps
+}
+
+func (d *definition) OnHook(hook LifecycleHook, instance *Instance) {
+	for _, fn := range d.hooks[hook] {
+		fn(instance)
+	}
+}
@@ -0,0 +1,130 @@
+package saga_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nguyenta1993/service-kit/saga/core"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+	_ "github.com/nguyenta1993/service-kit/saga/msgpack"
+	"github.com/nguyenta1993/service-kit/saga/saga"
+)
+
+type reserveStock struct{}
+
+func (reserveStock) CommandName() string        { return "saga_test.reserveStock" }
+func (reserveStock) DestinationChannel() string { return "inventory" }
+
+// unregisteredReply is never registered with core.RegisterReplies
+type unregisteredReply struct{}
+
+func (unregisteredReply) ReplyName() string { return "saga_test.unregisteredReply" }
+
+func TestDefinitionBuilder_Build(t *testing.T) {
+	command := func(context.Context, core.SagaData) msg.DomainCommand {
+		return reserveStock{}
+	}
+	handler := func(context.Context, core.SagaData, core.Reply) error { return nil }
+
+	tests := map[string]struct {
+		blankName         bool
+		blankReplyChannel bool
+		steps             []saga.Step
+		wantErr           bool
+	}{
+		"Valid": {
+			steps: []saga.Step{saga.NewRemoteStep().Action(command).Compensation(command).HandleActionReply(msg.Success{}, handler)},
+		},
+		"NonCompensatable": {
+			steps: []saga.Step{saga.NewRemoteStep().Action(command).NonCompensatable()},
+		},
+		"BlankName": {
+			blankName: true,
+			steps:     []saga.Step{saga.NewRemoteStep().Action(command).NonCompensatable()},
+			wantErr:   true,
+		},
+		"BlankReplyChannel": {
+			blankReplyChannel: true,
+			steps:             []saga.Step{saga.NewRemoteStep().Action(command).NonCompensatable()},
+			wantErr:           true,
+		},
+		"NoSteps": {
+			wantErr: true,
+		},
+		"UnregisteredReply": {
+			steps:   []saga.Step{saga.NewRemoteStep().Action(command).NonCompensatable().HandleActionReply(unregisteredReply{}, handler)},
+			wantErr: true,
+		},
+		"ActionWithoutCompensation": {
+			steps:   []saga.Step{saga.NewRemoteStep().Action(command)},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sagaName, replyChannel := "saga", "replies"
+			if tt.blankName {
+				sagaName = ""
+			}
+			if tt.blankReplyChannel {
+				replyChannel = ""
+			}
+
+			_, err := saga.NewDefinition(sagaName, replyChannel).Step(tt.steps...).Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDefinitionBuilder_StepOrder(t *testing.T) {
+	action := func(context.Context, core.SagaData) error { return nil }
+
+	compensatable := saga.NewLocalStep(action).Compensation(action)
+	pivot := saga.NewLocalStep(action).Pivot()
+	retriable := saga.NewLocalStep(action).Retriable()
+
+	tests := map[string]struct {
+		steps   []saga.Step
+		wantErr bool
+	}{
+		"CompensatableOnly": {
+			steps: []saga.Step{compensatable, compensatable},
+		},
+		"PivotThenRetriable": {
+			steps: []saga.Step{compensatable, pivot, retriable, retriable},
+		},
+		"RetriableWithoutPivot": {
+			steps: []saga.Step{compensatable, retriable},
+		},
+		"CompensatableAfterPivot": {
+			steps:   []saga.Step{pivot, compensatable},
+			wantErr: true,
+		},
+		"CompensatableAfterRetriable": {
+			steps:   []saga.Step{retriable, compensatable},
+			wantErr: true,
+		},
+		"PivotAfterRetriable": {
+			steps:   []saga.Step{retriable, pivot},
+			wantErr: true,
+		},
+		"TwoPivots": {
+			steps:   []saga.Step{pivot, pivot},
+			wantErr: true,
+		},
+		"PivotWithCompensation": {
+			steps:   []saga.Step{saga.NewLocalStep(action).Compensation(action).Pivot()},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := saga.NewDefinition("saga", "replies").Step(tt.steps...).Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package saga
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/nguyenta1993/service-kit/saga/core"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+)
+
+// Description is the structure of a saga definition, see Describe
+type Description struct {
+	SagaName     string            `json:"sagaName"`
+	ReplyChannel string            `json:"replyChannel"`
+	Version      int               `json:"version"`
+	Steps        []StepDescription `json:"steps"`
+}
+
+// StepDescription describes a step, or a branch of a parallel step
+type StepDescription struct {
+	Index int `json:"index"`
+	// Type is local, remote, parallel, await or sub-saga
+	Type string `json:"type"`
+	// Kind is compensatable, pivot or retriable
+	Kind         string             `json:"kind"`
+	Action       *ActionDescription `json:"action,omitempty"`
+	Compensation *ActionDescription `json:"compensation,omitempty"`
+	Branches     []StepDescription  `json:"branches,omitempty"`
+	// EventName is the event an await step waits for
+	EventName string `json:"eventName,omitempty"`
+	// SagaName is the child saga of a sub-saga step
+	SagaName string `json:"sagaName,omitempty"`
+}
+
+// ActionDescription describes the action or compensation of a step; the command needs sample saga data
+type ActionDescription struct {
+	CommandName string `json:"commandName,omitempty"`
+	Channel     string `json:"channel,omitempty"`
+	// Conditional is set when the action has a predicate
+	Conditional   bool          `json:"conditional,omitempty"`
+	Timeout       time.Duration `json:"timeout,omitempty"`
+	Retried       bool          `json:"retried,omitempty"`
+	ReplyHandlers []string      `json:"replyHandlers,omitempty"`
+}
+
+// Step types of a StepDescription
+const (
+	StepTypeLocal    = "local"
+	StepTypeRemote   = "remote"
+	StepTypeParallel = "parallel"
+	StepTypeAwait    = "await"
+	StepTypeSubSaga  = "sub-saga"
+)
+
+// Describe returns the structure of the definition, calling remote step actions with the WithDescribeSagaData samples
+func Describe(definition Definition, options ...DescribeOption) Description {
+	d := &describer{}
+
+	for _, option := range options {
+		option(d)
+	}
+
+	description := Description{
+		SagaName:     definition.SagaName(),
+		ReplyChannel: definition.ReplyChannel(),
+		Version:      DefinitionVersion(definition),
+		Steps:        make([]StepDescription, 0, len(definition.Steps())),
+	}
+
+	for i, step := range definition.Steps() {
+		stepDescription := d.step(step)
+		stepDescription.Index = i
+		description.Steps = append(description.Steps, stepDescription)
+	}
+
+	return description
+}
+
+// Describe returns the structure of the definition of new instances, see Describe
+func (o *Orchestrator) Describe(options ...DescribeOption) Description {
+	return Describe(o.definition, options...)
+}
+
+type describer struct {
+	samples []core.SagaData
+}
+
+func (d *describer) step(step Step) StepDescription {
+	description := StepDescription{Kind: step.kind().String()}
+
+	switch s := step.(type) {
+	case LocalStep:
+		description.Type = StepTypeLocal
+		if s.actions[notCompensating] != nil {
+			description.Action = &ActionDescription{}
+		}
+		if s.actions[isCompensating] != nil {
+			description.Compensation = &ActionDescription{}
+		}
+	case RemoteStep:
+		description.Type = StepTypeRemote
+		description.Action = d.remoteAction(s, notCompensating)
+		description.Compensation = d.remoteAction(s, isCompensating)
+	case ParallelStep:
+		description.Type = StepTypeParallel
+		for i, branch := range s.branches {
+			branchDescription := d.step(branch)
+			branchDescription.Index = i
+			description.Branches = append(description.Branches, branchDescription)
+		}
+	case AwaitStep:
+		description.Type = StepTypeAwait
+		description.EventName = s.EventName()
+		description.Action = &ActionDescription{Timeout: s.timeout}
+	case SubSagaStep:
+		description.Type = StepTypeSubSaga
+		description.SagaName = s.SagaName()
+		// compensating the step compensates the child saga
+		description.Action = &ActionDescription{}
+		description.Compensation = &ActionDescription{}
+	}
+
+	return description
+}
+
+func (d *describer) remoteAction(step RemoteStep, compensating bool) *ActionDescription {
+	action := step.actionHandlers[compensating]
+	if action == nil {
+		return nil
+	}
+
+	description := &ActionDescription{
+		Conditional: action.predicate != nil,
+		Timeout:     action.timeout,
+		Retried:     action.retryBackoff != nil,
+	}
+
+	for replyName := range step.replyHandlers[compensating] {
+		description.ReplyHandlers = append(description.ReplyHandlers, replyName)
+	}
+	sort.Strings(description.ReplyHandlers)
+
+	for _, sample := range d.samples {
+		if command := d.command(action, sample); command != nil {
+			description.CommandName = command.CommandName()
+			description.Channel = command.DestinationChannel()
+			break
+		}
+	}
+
+	return description
+}
+
+// command calls the action with the sample saga data; a sample of another type may make the action panic
+func (d *describer) command(action *remoteStepAction, sample core.SagaData) (command msg.DomainCommand) {
+	defer func() {
+		if recover() != nil {
+			command = nil
+		}
+	}()
+
+	return action.execute(context.Background(), sample)
+}
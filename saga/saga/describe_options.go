@@ -0,0 +1,15 @@
+package saga
+
+import (
+	"github.com/nguyenta1993/service-kit/saga/core"
+)
+
+// DescribeOption options for Describe
+type DescribeOption func(d *describer)
+
+// WithDescribeSagaData is an option to find the commands of remote steps from sample saga data
+func WithDescribeSagaData(samples ...core.SagaData) DescribeOption {
+	return func(d *describer) {
+		d.samples = append(d.samples, samples...)
+	}
+}
@@ -0,0 +1,80 @@
+package saga_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nguyenta1993/service-kit/saga/core"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+	"github.com/nguyenta1993/service-kit/saga/saga"
+)
+
+func TestDescribe(t *testing.T) {
+	command := func(channel string) func(context.Context, core.SagaData) msg.DomainCommand {
+		return func(_ context.Context, sagaData core.SagaData) msg.DomainCommand {
+			// actions assert their saga data; other samples panic
+			_ = sagaData.(*orderData)
+			return orderCommand{Channel: channel}
+		}
+	}
+
+	definition, err := saga.NewDefinition("saga_test.described", "described-replies").
+		Step(
+			saga.NewLocalStep(func(context.Context, core.SagaData) error { return nil }),
+			saga.NewRemoteStep().Action(command("inventory")).Compensation(command("inventory-release")),
+			saga.NewRemoteStep().Action(command("payment")).Pivot(),
+			saga.NewRemoteStep().Action(command("shipping")).Retriable(),
+		).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	description := saga.Describe(definition, saga.WithDescribeSagaData(&orderData{}))
+
+	if len(description.Steps) != 4 {
+		t.Fatalf("Describe() has %d steps, want 4", len(description.Steps))
+	}
+	if got := description.Steps[1].Compensation; got == nil || got.Channel != "inventory-release" {
+		t.Errorf("Describe() step 1 compensation = %+v, want a command to inventory-release", got)
+	}
+	if got := description.Steps[2].Kind; got != "pivot" {
+		t.Errorf("Describe() step 2 kind = %s, want pivot", got)
+	}
+
+	tests := map[string]struct {
+		render func(saga.Description) string
+		want   []string
+	}{
+		"Mermaid": {
+			render: saga.RenderMermaid,
+			want: []string{
+				`s1["1: remote<br/>saga_test.orderCommand → inventory"]`,
+				"s3 --> completed",
+				"s2 -. fails .-> c1",
+				"c1 --> compensated",
+				"s3 -. retry .-> s3",
+			},
+		},
+		"DOT": {
+			render: saga.RenderDOT,
+			want: []string{
+				`s1 [label="1: remote\nsaga_test.orderCommand → inventory"];`,
+				"s3 -> completed;",
+				`s2 -> c1 [label="fails" style=dashed];`,
+				"c1 -> compensated;",
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tt.render(description)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("rendered diagram does not contain %q:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
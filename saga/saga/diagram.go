@@ -0,0 +1,247 @@
+package saga
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RenderMermaid renders the description as a Mermaid flowchart, with compensations as dashed edges
+func RenderMermaid(description Description) string {
+	nodes, edges := diagramGraph(description)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "flowchart TD\n")
+	fmt.Fprintf(&b, "    %%%% %s v%d\n", description.SagaName, description.Version)
+	for _, node := range nodes {
+		label := strings.ReplaceAll(strings.Join(node.lines, "<br/>"), `"`, "#quot;")
+		if node.terminal {
+			fmt.Fprintf(&b, "    %s([\"%s\"])\n", node.id, label)
+		} else {
+			fmt.Fprintf(&b, "    %s[\"%s\"]\n", node.id, label)
+		}
+	}
+	for _, edge := range edges {
+		switch {
+		case edge.dashed:
+			fmt.Fprintf(&b, "    %s -. %s .-> %s\n", edge.from, edge.label, edge.to)
+		case edge.label != "":
+			fmt.Fprintf(&b, "    %s -- %s --> %s\n", edge.from, edge.label, edge.to)
+		default:
+			fmt.Fprintf(&b, "    %s --> %s\n", edge.from, edge.to)
+		}
+	}
+
+	return b.String()
+}
+
+// RenderDOT renders the description as a Graphviz DOT digraph, see RenderMermaid
+func RenderDOT(description Description) string {
+	nodes, edges := diagramGraph(description)
+
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	// lines are escaped one by one and joined with DOT line breaks
+	quote := func(lines ...string) string {
+		escaped := make([]string, len(lines))
+		for i, line := range lines {
+			escaped[i] = escape.Replace(line)
+		}
+		return `"` + strings.Join(escaped, `\n`) + `"`
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", quote(fmt.Sprintf("%s v%d", description.SagaName, description.Version)))
+	fmt.Fprintf(&b, "    rankdir=TB;\n    node [shape=box];\n")
+	for _, node := range nodes {
+		shape := ""
+		if node.terminal {
+			shape = " shape=oval"
+		}
+		fmt.Fprintf(&b, "    %s [label=%s%s];\n", node.id, quote(node.lines...), shape)
+	}
+	for _, edge := range edges {
+		var attributes []string
+		if edge.label != "" {
+			attributes = append(attributes, "label="+quote(edge.label))
+		}
+		if edge.dashed {
+			attributes = append(attributes, "style=dashed")
+		}
+		if len(attributes) > 0 {
+			fmt.Fprintf(&b, "    %s -> %s [%s];\n", edge.from, edge.to, strings.Join(attributes, " "))
+		} else {
+			fmt.Fprintf(&b, "    %s -> %s;\n", edge.from, edge.to)
+		}
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+type diagramNode struct {
+	id       string
+	lines    []string
+	terminal bool
+}
+
+type diagramEdge struct {
+	from, to string
+	label    string
+	dashed   bool
+}
+
+// diagramGraph returns the nodes and edges shared by the renderers
+func diagramGraph(description Description) ([]diagramNode, []diagramEdge) {
+	nodes := []diagramNode{{id: "start", lines: []string{"start"}, terminal: true}}
+	var edges []diagramEdge
+
+	previous := "start"
+	for _, step := range description.Steps {
+		id := fmt.Sprintf("s%d", step.Index)
+		nodes = append(nodes, diagramNode{id: id, lines: stepLines(step)})
+		edges = append(edges, diagramEdge{from: previous, to: id})
+		previous = id
+	}
+	nodes = append(nodes, diagramNode{id: "completed", lines: []string{"completed"}, terminal: true})
+	edges = append(edges, diagramEdge{from: previous, to: "completed"})
+
+	// compensations run in reverse order
+	compensations := map[int]string{}
+	lastCompensation := "compensated"
+	for _, step := range description.Steps {
+		if step.Compensation == nil && !hasBranchCompensation(step) {
+			continue
+		}
+		id := fmt.Sprintf("c%d", step.Index)
+		compensations[step.Index] = id
+		nodes = append(nodes, diagramNode{id: id, lines: compensationLines(step)})
+	}
+	for i := len(description.Steps) - 1; i >= 0; i-- {
+		if id, exists := compensations[description.Steps[i].Index]; exists {
+			if lastCompensation != "compensated" {
+				edges = append(edges, diagramEdge{from: lastCompensation, to: id})
+			}
+			lastCompensation = id
+		}
+	}
+	if len(compensations) > 0 {
+		edges = append(edges, diagramEdge{from: lastCompensation, to: "compensated"})
+	}
+
+	reachesCompensated := false
+	for i, step := range description.Steps {
+		id := fmt.Sprintf("s%d", step.Index)
+		if step.Kind == retriableStep.String() {
+			edges = append(edges, diagramEdge{from: id, to: id, label: "retry", dashed: true})
+			continue
+		}
+
+		target := "compensated"
+		from := i - 1
+		if step.Type == StepTypeParallel {
+			from = i
+		}
+		for j := from; j >= 0; j-- {
+			if compensation, exists := compensations[description.Steps[j].Index]; exists {
+				target = compensation
+				break
+			}
+		}
+		if target == "compensated" {
+			reachesCompensated = true
+		}
+		edges = append(edges, diagramEdge{from: id, to: target, label: "fails", dashed: true})
+	}
+	if len(compensations) > 0 || reachesCompensated {
+		nodes = append(nodes, diagramNode{id: "compensated", lines: []string{"compensated"}, terminal: true})
+	}
+
+	return nodes, edges
+}
+
+func stepLines(step StepDescription) []string {
+	title := fmt.Sprintf("%d: %s", step.Index, step.Type)
+	if step.Kind != compensatableStep.String() {
+		title += " (" + step.Kind + ")"
+	}
+	lines := []string{title}
+
+	switch step.Type {
+	case StepTypeParallel:
+		for _, branch := range step.Branches {
+			lines = append(lines, fmt.Sprintf("branch %d: %s", branch.Index, actionLine(branch.Action)))
+		}
+	case StepTypeAwait:
+		line := "await " + step.EventName
+		if step.Action != nil && step.Action.Timeout > 0 {
+			line += fmt.Sprintf(" (timeout %s)", step.Action.Timeout)
+		}
+		lines = append(lines, line)
+	case StepTypeSubSaga:
+		lines = append(lines, "saga "+step.SagaName)
+	case StepTypeRemote:
+		lines = append(lines, actionLine(step.Action))
+		if step.Action != nil && len(step.Action.ReplyHandlers) > 0 {
+			lines = append(lines, "handles "+strings.Join(step.Action.ReplyHandlers, ", "))
+		}
+	}
+
+	return lines
+}
+
+func compensationLines(step StepDescription) []string {
+	lines := []string{fmt.Sprintf("%d: compensate", step.Index)}
+
+	switch step.Type {
+	case StepTypeParallel:
+		for _, branch := range step.Branches {
+			if branch.Compensation != nil {
+				lines = append(lines, fmt.Sprintf("branch %d: %s", branch.Index, actionLine(branch.Compensation)))
+			}
+		}
+	case StepTypeSubSaga:
+		lines = append(lines, "saga "+step.SagaName)
+	case StepTypeRemote:
+		lines = append(lines, actionLine(step.Compensation))
+		if len(step.Compensation.ReplyHandlers) > 0 {
+			lines = append(lines, "handles "+strings.Join(step.Compensation.ReplyHandlers, ", "))
+		}
+	}
+
+	return lines
+}
+
+// actionLine describes the command of a remote action
+func actionLine(action *ActionDescription) string {
+	if action == nil {
+		return "no action"
+	}
+
+	line := "command"
+	if action.CommandName != "" {
+		line = action.CommandName
+	}
+	if action.Channel != "" {
+		line += " → " + action.Channel
+	}
+	if action.Conditional {
+		line += " (conditional)"
+	}
+	if action.Retried {
+		line += " (retried)"
+	}
+	if action.Timeout > 0 {
+		line += fmt.Sprintf(" (timeout %s)", action.Timeout)
+	}
+
+	return line
+}
+
+func hasBranchCompensation(step StepDescription) bool {
+	for _, branch := range step.Branches {
+		if branch.Compensation != nil {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,30 @@
+package saga
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Instance errors
+var (
+	ErrInstanceNotFound   = errors.New("saga instance not found")
+	ErrSagaEnded          = errors.New("saga instance has already ended")
+	ErrSagaCompensating   = errors.New("saga instance is already compensating")
+	ErrSagaPastPivot      = errors.New("saga instance has reached its pivot step and cannot be compensated")
+	ErrSagaCancelled      = errors.New("saga instance has already been cancelled")
+	ErrHistoryNotRecorded = errors.New("saga history is not recorded")
+
+	ErrDefinitionVersionNotHosted = errors.New("saga definition version is not hosted by the orchestrator")
+	ErrStoreNotSupported          = errors.New("saga instance store does not support the operation")
+)
+
+// ErrInstanceConflict is returned by an InstanceStore when an instance was updated by someone else since it was found
+type ErrInstanceConflict struct {
+	SagaName string
+	SagaID   string
+	Version  int
+}
+
+func (e *ErrInstanceConflict) Error() string {
+	return fmt.Sprintf("saga instance %s/%s was modified since version %d", e.SagaName, e.SagaID, e.Version)
+}
@@ -0,0 +1,49 @@
+package saga
+
+import (
+	"time"
+)
+
+// ExecutionState is the state of an instance beyond its current step, saved as JSON by an InstanceStore
+type ExecutionState struct {
+	Branches map[int][]BranchState `json:"branches,omitempty"`
+	// Attempts is the number of times the action of the current step has failed and been retried
+	Attempts int `json:"attempts,omitempty"`
+	// FailedAt is when the action of the current step first failed; retries stop once the backoff maximum has elapsed
+	FailedAt time.Time `json:"failedAt"`
+	// Retrying is set while the current step waits for the delay before its action is sent again
+	Retrying bool `json:"retrying,omitempty"`
+	// Cancelled is set by Orchestrator.Cancel; the instance is compensated once its current step replies
+	Cancelled    bool   `json:"cancelled,omitempty"`
+	CancelReason string `json:"cancelReason,omitempty"`
+	// Reverted is set on a child saga that is compensated because its parent is compensating
+	Reverted bool `json:"reverted,omitempty"`
+	// ParentStep is the step of the parent saga that last executed this child saga, echoed in its reply
+	ParentStep *ParentStep `json:"parentStep,omitempty"`
+	// Sequence is incremented each time commands are sent for the instance, e.g. when a step is retried or resent
+	Sequence int `json:"sequence,omitempty"`
+	// StepStartedAt is when the current step, or its compensation, was executed
+	StepStartedAt time.Time `json:"stepStartedAt"`
+	// TraceContext is the propagated trace context of the root span of the instance
+	TraceContext map[string]string `json:"traceContext,omitempty"`
+}
+
+// ParentStep is the step, direction and send sequence of a parent saga executing a child saga in a SubSagaStep
+type ParentStep struct {
+	Step         int  `json:"step"`
+	Compensating bool `json:"compensating"`
+	Sequence     int  `json:"sequence"`
+}
+
+// BranchState is the state of a single branch of a ParallelStep
+type BranchState string
+
+// Branch states
+const (
+	BranchPending      BranchState = "pending"
+	BranchSucceeded    BranchState = "succeeded"
+	BranchFailed       BranchState = "failed"
+	BranchSkipped      BranchState = "skipped"
+	BranchCompensating BranchState = "compensating"
+	BranchCompensated  BranchState = "compensated"
+)
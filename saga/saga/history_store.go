@@ -0,0 +1,93 @@
+package saga
+
+import (
+	"context"
+	"time"
+
+	"github.com/nguyenta1993/service-kit/saga/msg"
+)
+
+// HistoryStore is an append only record of the transitions of saga instances
+type HistoryStore interface {
+	Append(ctx context.Context, record HistoryRecord) error
+	// Timeline returns the records of an instance in the order they were appended
+	Timeline(ctx context.Context, sagaName, sagaID string) ([]HistoryRecord, error)
+}
+
+// HistoryEvent is what caused a saga instance transition
+type HistoryEvent string
+
+// History events
+const (
+	HistoryStarted            HistoryEvent = "started"
+	HistoryReplied            HistoryEvent = "replied"
+	HistoryLocalSuccess       HistoryEvent = "local_success"
+	HistoryLocalFailure       HistoryEvent = "local_failure"
+	HistoryTimedOut           HistoryEvent = "timed_out"
+	HistoryRetried            HistoryEvent = "retried"
+	HistoryCancelled          HistoryEvent = "cancelled"
+	HistoryEventReceived      HistoryEvent = "event_received"
+	HistoryResent             HistoryEvent = "resent"
+	HistoryForcedCompensation HistoryEvent = "forced_compensation"
+	HistoryForcedCompletion   HistoryEvent = "forced_completion"
+)
+
+// HistoryRecord is a single transition of a saga instance and its state after it
+type HistoryRecord struct {
+	SagaName       string           `json:"sagaName"`
+	SagaID         string           `json:"sagaId"`
+	Step           int              `json:"step"`
+	Compensating   bool             `json:"compensating"`
+	EndState       bool             `json:"endState"`
+	Event          HistoryEvent     `json:"event"`
+	ReplyName      string           `json:"replyName,omitempty"`
+	ReplyOutcome   string           `json:"replyOutcome,omitempty"`
+	ReplyMessageID string           `json:"replyMessageId,omitempty"`
+	Commands       []HistoryCommand `json:"commands,omitempty"`
+	Error          string           `json:"error,omitempty"`
+	CreatedAt      time.Time        `json:"createdAt"`
+}
+
+// HistoryCommand is a command sent by a saga instance transition
+type HistoryCommand struct {
+	CommandName string `json:"commandName"`
+	MessageID   string `json:"messageId"`
+}
+
+// Timeline returns the history of an instance; ErrHistoryNotRecorded is returned without a HistoryStore
+func (o *Orchestrator) Timeline(ctx context.Context, sagaID string) ([]HistoryRecord, error) {
+	if o.historyStore == nil {
+		return nil, ErrHistoryNotRecorded
+	}
+
+	return o.historyStore.Timeline(ctx, o.definition.SagaName(), sagaID)
+}
+
+func (o *Orchestrator) appendHistory(ctx context.Context, instance *Instance, cause historyCause, commands []HistoryCommand) error {
+	if o.historyStore == nil {
+		return nil
+	}
+
+	record := HistoryRecord{
+		SagaName:     instance.sagaName,
+		SagaID:       instance.sagaID,
+		Step:         instance.currentStep,
+		Compensating: instance.compensating,
+		EndState:     instance.endState,
+		Event:        cause.event,
+		Commands:     commands,
+		CreatedAt:    time.Now(),
+	}
+
+	if cause.reply != nil {
+		record.ReplyName = cause.reply.Reply().ReplyName()
+		record.ReplyOutcome = cause.reply.Headers().Get(msg.MessageReplyOutcome)
+		record.ReplyMessageID = cause.reply.Headers().Get(msg.MessageID)
+	}
+
+	if cause.err != nil {
+		record.Error = cause.err.Error()
+	}
+
+	return o.historyStore.Append(ctx, record)
+}
@@ -1,20 +1,34 @@
 package saga
 
-import "github.com/nguyenta1993/service-kit/saga/core"
+import (
+	"time"
+
+	"github.com/nguyenta1993/service-kit/saga/core"
+)
 
 // Instance is the container for saga data
 type Instance struct {
-	sagaID       string
-	sagaName     string
-	sagaData     core.SagaData
-	currentStep  int
-	endState     bool
-	compensating bool
+	sagaID         string
+	sagaName       string
+	sagaData       core.SagaData
+	currentStep    int
+	endState       bool
+	compensating   bool
+	deadline       time.Time
+	stepDeadline   time.Time
+	version        int
+	modifiedAt     time.Time
+	executionState ExecutionState
+	correlationKey string
+	parentSagaName string
+	parentSagaID   string
+	// definitionVersion is the version of the definition that started the instance
+	definitionVersion int
 }
 
 // NewSagaInstance constructor for *SagaInstances
-func NewSagaInstance(sagaName, sagaID string, sagaData core.SagaData, currentStep int, endState, compensating bool) *Instance {
-	return &Instance{
+func NewSagaInstance(sagaName, sagaID string, sagaData core.SagaData, currentStep int, endState, compensating bool, options ...InstanceOption) *Instance {
+	i := &Instance{
 		sagaID:       sagaID,
 		sagaName:     sagaName,
 		sagaData:     sagaData,
@@ -22,6 +36,12 @@ func NewSagaInstance(sagaName, sagaID string, sagaData core.SagaData, currentSte
 		endState:     endState,
 		compensating: compensating,
 	}
+
+	for _, option := range options {
+		option(i)
+	}
+
+	return i
 }
 
 // SagaID returns the instance saga id
@@ -54,11 +74,78 @@ func (i *Instance) Compensating() bool {
 	return i.compensating
 }
 
+// Deadline returns the time the saga must complete by; a zero time means there isn't a deadline
+func (i *Instance) Deadline() time.Time {
+	return i.deadline
+}
+
+// StepDeadline returns the time the current step must receive a reply by; a zero time means there isn't a deadline
+func (i *Instance) StepDeadline() time.Time {
+	return i.stepDeadline
+}
+
+// Overdue returns whether or not the current step or the saga has missed its deadline
+func (i *Instance) Overdue(now time.Time) bool {
+	if i.endState {
+		return false
+	}
+
+	if !i.stepDeadline.IsZero() && i.stepDeadline.Before(now) {
+		return true
+	}
+
+	return !i.compensating && !i.deadline.IsZero() && i.deadline.Before(now)
+}
+
+// Version returns the version of the instance when it was found; it is increased with each update
+func (i *Instance) Version() int {
+	return i.version
+}
+
+// ModifiedAt returns the time the instance was last saved when it was found
+func (i *Instance) ModifiedAt() time.Time {
+	return i.modifiedAt
+}
+
+// CorrelationKey returns the key events are matched with while the instance waits in an AwaitStep
+func (i *Instance) CorrelationKey() string {
+	return i.correlationKey
+}
+
+// ParentSagaName returns the name of the saga that started the instance in a SubSagaStep
+func (i *Instance) ParentSagaName() string {
+	return i.parentSagaName
+}
+
+// ParentSagaID returns the ID of the saga that started the instance in a SubSagaStep; it is empty for other instances
+func (i *Instance) ParentSagaID() string {
+	return i.parentSagaID
+}
+
+// DefinitionVersion returns the version of the definition that started the instance
+func (i *Instance) DefinitionVersion() int {
+	if i.definitionVersion < 1 {
+		return 1
+	}
+
+	return i.definitionVersion
+}
+
+// ExecutionState returns the state of the instance beyond its current step
+func (i *Instance) ExecutionState() ExecutionState {
+	return i.executionState
+}
+
 func (i *Instance) getStepContext() stepContext {
 	return stepContext{
 		step:         i.currentStep,
 		compensating: i.compensating,
 		ended:        i.endState,
+		branches:     i.executionState.Branches,
+		attempts:     i.executionState.Attempts,
+		failedAt:     i.executionState.FailedAt,
+		retrying:     i.executionState.Retrying,
+		cancelled:    i.executionState.Cancelled,
 	}
 }
 
@@ -66,4 +153,9 @@ func (i *Instance) updateStepContext(stepCtx stepContext) {
 	i.currentStep = stepCtx.step
 	i.endState = stepCtx.ended
 	i.compensating = stepCtx.compensating
+	i.executionState.Branches = stepCtx.branches
+	i.executionState.Attempts = stepCtx.attempts
+	i.executionState.FailedAt = stepCtx.failedAt
+	i.executionState.Retrying = stepCtx.retrying
+	i.executionState.Cancelled = stepCtx.cancelled
 }
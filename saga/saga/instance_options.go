@@ -0,0 +1,65 @@
+package saga
+
+import (
+	"time"
+)
+
+// InstanceOption options for Instance
+type InstanceOption func(i *Instance)
+
+// WithInstanceDeadline sets the time the saga must complete by
+func WithInstanceDeadline(deadline time.Time) InstanceOption {
+	return func(i *Instance) {
+		i.deadline = deadline
+	}
+}
+
+// WithInstanceStepDeadline sets the time the current step must receive a reply by
+func WithInstanceStepDeadline(stepDeadline time.Time) InstanceOption {
+	return func(i *Instance) {
+		i.stepDeadline = stepDeadline
+	}
+}
+
+// WithInstanceVersion sets the version the instance was found with
+func WithInstanceVersion(version int) InstanceOption {
+	return func(i *Instance) {
+		i.version = version
+	}
+}
+
+// WithInstanceModifiedAt sets the time the instance was last saved
+func WithInstanceModifiedAt(modifiedAt time.Time) InstanceOption {
+	return func(i *Instance) {
+		i.modifiedAt = modifiedAt
+	}
+}
+
+// WithInstanceExecutionState sets the state of the instance beyond its current step
+func WithInstanceExecutionState(executionState ExecutionState) InstanceOption {
+	return func(i *Instance) {
+		i.executionState = executionState
+	}
+}
+
+// WithInstanceCorrelationKey sets the key events are matched with while the instance waits in an AwaitStep
+func WithInstanceCorrelationKey(correlationKey string) InstanceOption {
+	return func(i *Instance) {
+		i.correlationKey = correlationKey
+	}
+}
+
+// WithInstanceParent sets the saga that started the instance in a SubSagaStep
+func WithInstanceParent(parentSagaName, parentSagaID string) InstanceOption {
+	return func(i *Instance) {
+		i.parentSagaName = parentSagaName
+		i.parentSagaID = parentSagaID
+	}
+}
+
+// WithInstanceDefinitionVersion sets the version of the definition that started the instance
+func WithInstanceDefinitionVersion(definitionVersion int) InstanceOption {
+	return func(i *Instance) {
+		i.definitionVersion = definitionVersion
+	}
+}
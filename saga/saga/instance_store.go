@@ -2,11 +2,57 @@ package saga
 
 import (
 	"context"
+	"time"
 )
 
 // InstanceStore interface
 type InstanceStore interface {
 	Find(ctx context.Context, sagaName, sagaID string) (*Instance, error)
 	Save(ctx context.Context, sagaInstance *Instance) error
+	// Update saves the next version of the instance or returns an *ErrInstanceConflict
 	Update(ctx context.Context, sagaInstance *Instance) error
 }
+
+// OverdueFinder is implemented by the InstanceStores a TimeoutScheduler can find overdue instances in
+type OverdueFinder interface {
+	// FindOverdue returns up to limit instances that are Overdue at the given time
+	FindOverdue(ctx context.Context, sagaName string, now time.Time, limit int) ([]*Instance, error)
+}
+
+// InstanceLister is implemented by the InstanceStores that can list instances
+type InstanceLister interface {
+	// List returns the instances matching the query ordered by the time they were last modified
+	List(ctx context.Context, query InstanceQuery) ([]*Instance, error)
+}
+
+// CorrelationKeyFinder is implemented by the InstanceStores that can find the instances waiting in an AwaitStep
+type CorrelationKeyFinder interface {
+	// FindByCorrelationKey returns the running instance waiting with the correlation key or ErrInstanceNotFound
+	FindByCorrelationKey(ctx context.Context, sagaName, correlationKey string) (*Instance, error)
+}
+
+// ChildFinder is implemented by the InstanceStores that can find the instances started by SubSagaSteps
+type ChildFinder interface {
+	// FindChildren returns the instances started by the SubSagaSteps of the parent instance
+	FindChildren(ctx context.Context, parentSagaName, parentSagaID string) ([]*Instance, error)
+}
+
+// InstanceState is the state of an instance used to filter instance lists
+type InstanceState string
+
+// Instance states
+const (
+	InstanceRunning      InstanceState = "running"
+	InstanceCompensating InstanceState = "compensating"
+	InstanceEnded        InstanceState = "ended"
+)
+
+// InstanceQuery filters the instances returned by InstanceLister.List; zero values are not used to filter
+type InstanceQuery struct {
+	SagaName          string
+	State             InstanceState
+	ModifiedBefore    time.Time
+	DefinitionVersion int
+	Limit             int
+	Offset            int
+}
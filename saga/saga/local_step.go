@@ -2,13 +2,15 @@ package saga
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/nguyenta1993/service-kit/saga/core"
 )
 
 // LocalStep is used to execute local saga business logic
 type LocalStep struct {
-	actions map[bool]func(context.Context, core.SagaData) error
+	actions  map[bool]func(context.Context, core.SagaData) error
+	stepKind stepKind
 }
 
 var _ Step = (*LocalStep)(nil)
@@ -28,6 +30,18 @@ func (s LocalStep) Compensation(compensation func(context.Context, core.SagaData
 	return s
 }
 
+// Pivot marks the step as the point of no return; once it succeeds the saga is no longer compensated
+func (s LocalStep) Pivot() LocalStep {
+	s.stepKind = pivotStep
+	return s
+}
+
+// Retriable marks the step as one that will eventually succeed; a failed action is retried until it does
+func (s LocalStep) Retriable() LocalStep {
+	s.stepKind = retriableStep
+	return s
+}
+
 func (s LocalStep) hasInvocableAction(_ context.Context, _ core.SagaData, compensating bool) bool {
 	return s.actions[compensating] != nil
 }
@@ -43,3 +57,24 @@ func (s LocalStep) execute(ctx context.Context, sagaData core.SagaData, compensa
 		results.failure = err
 	}
 }
+
+func (s LocalStep) validate() error {
+	if s.actions[notCompensating] == nil && s.actions[isCompensating] == nil {
+		return fmt.Errorf("local step has neither an action nor a compensation")
+	}
+
+	if s.stepKind != compensatableStep {
+		if s.actions[notCompensating] == nil {
+			return fmt.Errorf("%s local step has no action", s.stepKind)
+		}
+		if s.actions[isCompensating] != nil {
+			return fmt.Errorf("%s local step has a compensation; it is never compensated", s.stepKind)
+		}
+	}
+
+	return nil
+}
+
+func (s LocalStep) kind() stepKind {
+	return s.stepKind
+}
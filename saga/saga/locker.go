@@ -0,0 +1,10 @@
+package saga
+
+import (
+	"context"
+)
+
+// Locker guards work that only one process should do at a time
+type Locker interface {
+	TryLock(ctx context.Context) (unlock func(context.Context) error, locked bool, err error)
+}
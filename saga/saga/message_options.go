@@ -1,11 +1,21 @@
 package saga
 
-import "github.com/nguyenta1993/service-kit/saga/msg"
+import (
+	"strconv"
+
+	"github.com/nguyenta1993/service-kit/saga/msg"
+)
 
 // WithSagaInfo is an option to set additional Saga specific headers
+//
+// The step, direction and send sequence of the instance are echoed by the reply so replies to earlier steps, or to
+// earlier sends of the same step, can be dropped.
 func WithSagaInfo(instance *Instance) msg.MessageOption {
 	return msg.WithHeaders(map[string]string{
-		MessageCommandSagaID:   instance.sagaID,
-		MessageCommandSagaName: instance.sagaName,
+		MessageCommandSagaID:           instance.sagaID,
+		MessageCommandSagaName:         instance.sagaName,
+		MessageCommandSagaStep:         strconv.Itoa(instance.currentStep),
+		MessageCommandSagaCompensating: strconv.FormatBool(instance.compensating),
+		MessageCommandSagaSequence:     strconv.Itoa(instance.executionState.Sequence),
 	})
 }
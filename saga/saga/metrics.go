@@ -0,0 +1,146 @@
+package saga
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Metrics are the Prometheus metrics recorded by the orchestrators given WithOrchestratorMetrics
+type Metrics struct {
+	started       *prometheus.CounterVec
+	completed     *prometheus.CounterVec
+	compensated   *prometheus.CounterVec
+	compensations *prometheus.CounterVec
+	inFlight      *prometheus.GaugeVec
+	stepDuration  *prometheus.HistogramVec
+	staleReplies  *prometheus.CounterVec
+}
+
+// NewMetrics constructs and registers the saga Metrics; construct them once and share them between orchestrators
+func NewMetrics(registerer prometheus.Registerer) *Metrics {
+	factory := promauto.With(registerer)
+
+	return &Metrics{
+		started: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "saga_started_total",
+			Help: "Saga instances started",
+		}, []string{"saga_name"}),
+		completed: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "saga_completed_total",
+			Help: "Saga instances completed",
+		}, []string{"saga_name"}),
+		compensated: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "saga_compensated_total",
+			Help: "Saga instances compensated",
+		}, []string{"saga_name"}),
+		compensations: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "saga_compensations_total",
+			Help: "Saga instances that began compensating",
+		}, []string{"saga_name"}),
+		inFlight: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "saga_in_flight",
+			Help: "Saga instances started and not yet ended",
+		}, []string{"saga_name"}),
+		stepDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "saga_step_duration_seconds",
+			Help:    "Time from executing a saga step until the saga moves to another step",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60, 300},
+		}, []string{"saga_name", "step", "compensating"}),
+		staleReplies: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "saga_stale_replies_total",
+			Help: "Saga replies dropped because they were sent for a step the instance is no longer on",
+		}, []string{"saga_name"}),
+	}
+}
+
+// the methods are no-ops on nil Metrics so orchestrators without metrics need no checks
+
+func (m *Metrics) sagaStarted(sagaName string) {
+	if m == nil {
+		return
+	}
+
+	m.started.WithLabelValues(sagaName).Inc()
+	m.inFlight.WithLabelValues(sagaName).Inc()
+}
+
+func (m *Metrics) sagaRestarted(sagaName string) {
+	if m == nil {
+		return
+	}
+
+	m.inFlight.WithLabelValues(sagaName).Inc()
+}
+
+func (m *Metrics) sagaEnded(sagaName string, compensated bool) {
+	if m == nil {
+		return
+	}
+
+	if compensated {
+		m.compensated.WithLabelValues(sagaName).Inc()
+	} else {
+		m.completed.WithLabelValues(sagaName).Inc()
+	}
+	m.inFlight.WithLabelValues(sagaName).Dec()
+}
+
+func (m *Metrics) compensationStarted(sagaName string) {
+	if m == nil {
+		return
+	}
+
+	m.compensations.WithLabelValues(sagaName).Inc()
+}
+
+func (m *Metrics) stepEnded(sagaName string, step int, compensating bool, duration time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.stepDuration.WithLabelValues(sagaName, strconv.Itoa(step), strconv.FormatBool(compensating)).Observe(duration.Seconds())
+}
+
+func (m *Metrics) replyDropped(sagaName string) {
+	if m == nil {
+		return
+	}
+
+	m.staleReplies.WithLabelValues(sagaName).Inc()
+}
+
+// recordStep records the metrics and span of the step the instance has moved on from
+func (o *Orchestrator) recordStep(ctx context.Context, instance *Instance, previous stepContext, cause historyCause) {
+	state := &instance.executionState
+
+	sagaName, compensating, ended := instance.sagaName, instance.compensating, instance.endState
+
+	moved := previous.step != instance.currentStep || previous.compensating != compensating || ended
+	if moved && !state.StepStartedAt.IsZero() {
+		failed := !previous.compensating && compensating
+		duration := time.Since(state.StepStartedAt)
+		afterSession(ctx, func(context.Context) {
+			o.metrics.stepEnded(sagaName, previous.step, previous.compensating, duration)
+		})
+		o.recordStepSpan(ctx, instance, previous, state.StepStartedAt, failed, cause)
+		state.StepStartedAt = time.Time{}
+	}
+
+	if state.StepStartedAt.IsZero() && !ended {
+		state.StepStartedAt = time.Now()
+	}
+
+	afterSession(ctx, func(context.Context) {
+		if compensating && !previous.compensating {
+			o.metrics.compensationStarted(sagaName)
+		}
+		// a completed child saga is compensated again when its parent compensates
+		if previous.ended && !ended {
+			o.metrics.sagaRestarted(sagaName)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package saga_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/memory"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+	"github.com/nguyenta1993/service-kit/saga/saga"
+)
+
+func TestOrchestrator_Metrics(t *testing.T) {
+	ctx := context.Background()
+	log := logger.NewDefaultLogger("error")
+
+	definition, err := saga.NewDefinition("saga_test.measured", "measured-replies").
+		Step(remoteStep("inventory")).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	registry := prometheus.NewRegistry()
+	broker := memory.NewBroker(log)
+	orchestrator := saga.NewOrchestrator(definition, memory.NewSagaInstanceStore(), msg.NewPublisher(broker.Producer(), log), log,
+		saga.WithOrchestratorMetrics(saga.NewMetrics(registry)),
+	)
+
+	started, err := orchestrator.Start(ctx, &orderData{OrderID: "order-id"})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err = orchestrator.ReceiveMessage(ctx, successReply(t, started.SagaName(), started.SagaID())); err != nil {
+		t.Fatalf("ReceiveMessage() error = %v", err)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	got := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			switch {
+			case metric.GetCounter() != nil:
+				got[family.GetName()] += metric.GetCounter().GetValue()
+			case metric.GetGauge() != nil:
+				got[family.GetName()] += metric.GetGauge().GetValue()
+			case metric.GetHistogram() != nil:
+				got[family.GetName()] += float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+
+	want := map[string]float64{
+		"saga_started_total":         1,
+		"saga_completed_total":       1,
+		"saga_in_flight":             0,
+		"saga_step_duration_seconds": 1,
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("%s = %v, want %v", name, got[name], value)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package saga
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultWaitPollInterval is how often StartAndWait finds the instance when it has not been notified
+const DefaultWaitPollInterval = time.Second
+
+// Notifier tells the callers waiting in Orchestrator.StartAndWait that an instance has ended
+type Notifier interface {
+	Notify(ctx context.Context, sagaName, sagaID string) error
+	// Listen returns a channel that is closed once the instance has ended; stop must be called when done waiting
+	Listen(sagaName, sagaID string) (ended <-chan struct{}, stop func())
+}
+
+// LocalNotifier is a Notifier for the listeners of a single process
+type LocalNotifier struct {
+	mu        sync.Mutex
+	listeners map[string]map[chan struct{}]struct{}
+}
+
+var _ Notifier = (*LocalNotifier)(nil)
+
+// NewLocalNotifier constructs a new LocalNotifier
+func NewLocalNotifier() *LocalNotifier {
+	return &LocalNotifier{
+		listeners: map[string]map[chan struct{}]struct{}{},
+	}
+}
+
+// Notify implements Notifier.Notify
+func (n *LocalNotifier) Notify(_ context.Context, sagaName, sagaID string) error {
+	key := sagaName + "/" + sagaID
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for listener := range n.listeners[key] {
+		close(listener)
+	}
+	delete(n.listeners, key)
+
+	return nil
+}
+
+// Listen implements Notifier.Listen
+func (n *LocalNotifier) Listen(sagaName, sagaID string) (<-chan struct{}, func()) {
+	key := sagaName + "/" + sagaID
+	listener := make(chan struct{})
+
+	n.mu.Lock()
+	if _, exists := n.listeners[key]; !exists {
+		n.listeners[key] = map[chan struct{}]struct{}{}
+	}
+	n.listeners[key][listener] = struct{}{}
+	n.mu.Unlock()
+
+	return listener, func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+
+		// the listener is gone once it has been notified
+		if listeners, exists := n.listeners[key]; exists {
+			delete(listeners, listener)
+			if len(listeners) == 0 {
+				delete(n.listeners, key)
+			}
+		}
+	}
+}
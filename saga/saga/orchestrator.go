@@ -3,6 +3,7 @@ package saga
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -10,14 +11,38 @@ import (
 	"github.com/nguyenta1993/service-kit/logger"
 	"github.com/nguyenta1993/service-kit/saga/core"
 	"github.com/nguyenta1993/service-kit/saga/msg"
+	"github.com/nguyenta1993/service-kit/saga/retry"
 )
 
 // Orchestrator orchestrates local and distributed processes
 type Orchestrator struct {
-	definition    Definition
-	instanceStore InstanceStore
-	publisher     msg.CommandMessagePublisher
-	logger        logger.Logger
+	definition      Definition
+	instanceStore   InstanceStore
+	publisher       msg.CommandMessagePublisher
+	logger          logger.Logger
+	sagaTimeout     time.Duration
+	conflictRetryer retry.Retryer
+	// session is the unit of work of each attempt; a conflicting attempt is rolled back before it is tried again
+	session      Session
+	historyStore HistoryStore
+	// retriableBackoff is used by retriable steps without a backoff of their own
+	retriableBackoff *retry.Backoff
+	// previousDefinitions are the earlier versions of the definition still executing instances
+	previousDefinitions []Definition
+	// versions are the orchestrators of every hosted version of the definition, including this one
+	versions map[int]*Orchestrator
+	parents  *subSagaParents
+	// notifier wakes the callers of StartAndWait; waitPollInterval is how often they find the instance regardless
+	notifier         Notifier
+	waitPollInterval time.Duration
+	metrics          *Metrics
+}
+
+// historyCause is what caused the results being processed
+type historyCause struct {
+	event HistoryEvent
+	reply msg.Reply
+	err   error
 }
 
 const sagaNotStarted = -1
@@ -31,13 +56,52 @@ func NewOrchestrator(definition Definition, store InstanceStore, publisher msg.C
 		instanceStore: store,
 		publisher:     publisher,
 		logger:        logger,
+		conflictRetryer: retry.NewExponentialBackoff(
+			retry.WithBackoffInitialInterval(10*time.Millisecond),
+			retry.WithBackoffMaxInterval(time.Second),
+			retry.WithBackoffMaxRetries(5),
+		),
+		session:          noSession,
+		retriableBackoff: retry.NewExponentialBackoff(),
+		parents:          &subSagaParents{replyChannels: map[string]string{}},
+		notifier:         NewLocalNotifier(),
+		waitPollInterval: DefaultWaitPollInterval,
 	}
 
 	for _, option := range options {
 		option(o)
 	}
 
-	o.logger.Info("saga.Orchestrator constructed", zap.String("SagaName", definition.SagaName()))
+	o.versions = map[int]*Orchestrator{DefinitionVersion(definition): o}
+	for _, previous := range o.previousDefinitions {
+		version := DefinitionVersion(previous)
+		switch _, exists := o.versions[version]; {
+		case previous.SagaName() != definition.SagaName() || previous.ReplyChannel() != definition.ReplyChannel():
+			o.logger.Error("ignoring saga definition; versions must have the same saga name and reply channel",
+				zap.String("SagaName", previous.SagaName()),
+				zap.Int("DefinitionVersion", version),
+			)
+		case exists:
+			o.logger.Error("ignoring saga definition; the version is already hosted",
+				zap.String("SagaName", previous.SagaName()),
+				zap.Int("DefinitionVersion", version),
+			)
+		default:
+			v := *o
+			v.definition = previous
+			o.versions[version] = &v
+		}
+	}
+
+	for _, v := range o.versions {
+		for _, step := range v.definition.Steps() {
+			if subSagaStep, ok := step.(SubSagaStep); ok && subSagaStep.orchestrator != nil {
+				subSagaStep.orchestrator.addParent(definition.SagaName(), definition.ReplyChannel())
+			}
+		}
+	}
+
+	o.logger.Info("saga.Orchestrator constructed", zap.String("SagaName", definition.SagaName()), zap.Int("DefinitionVersion", DefinitionVersion(definition)))
 
 	return o
 }
@@ -50,10 +114,37 @@ func (o *Orchestrator) Start(ctx context.Context, sagaData core.SagaData) (*Inst
 		sagaData: sagaData,
 	}
 
+	return o.startInSession(ctx, instance)
+}
+
+// startInSession starts the instance in a session of its own
+func (o *Orchestrator) startInSession(ctx context.Context, instance *Instance) (started *Instance, err error) {
+	err = o.inSession(ctx, func(ctx context.Context) error {
+		started, err = o.start(ctx, instance)
+		return err
+	})
+
+	return started, err
+}
+
+func (o *Orchestrator) start(ctx context.Context, instance *Instance) (*Instance, error) {
+	sagaData := instance.sagaData
+	instance.definitionVersion = DefinitionVersion(o.definition)
+
+	if o.sagaTimeout > 0 {
+		instance.deadline = time.Now().Add(o.sagaTimeout)
+	}
+
+	ctx, span := o.startSagaSpan(ctx, instance)
+	defer span.End()
+
 	err := o.instanceStore.Save(ctx, instance)
 	if err != nil {
 		return nil, err
 	}
+	afterSession(ctx, func(context.Context) {
+		o.metrics.sagaStarted(instance.sagaName)
+	})
 
 	logger := o.logger.With(
 		zap.String("SagaName", o.definition.SagaName()),
@@ -66,10 +157,10 @@ func (o *Orchestrator) Start(ctx context.Context, sagaData core.SagaData) (*Inst
 	results := o.executeNextStep(ctx, stepContext{step: sagaNotStarted}, sagaData)
 	if results.failure != nil {
 		logger.Error("error while starting saga orchestration", zap.Error(results.failure))
-		return nil, err
+		return nil, results.failure
 	}
 
-	err = o.processResults(ctx, instance, results)
+	err = o.processResults(ctx, instance, results, historyCause{event: HistoryStarted})
 	if err != nil {
 		logger.Error("error while processing results", zap.Error(err))
 		return nil, err
@@ -83,6 +174,11 @@ func (o *Orchestrator) ReplyChannel() string {
 	return o.definition.ReplyChannel()
 }
 
+// ReceiverName implements msg.NamedMessageReceiver.ReceiverName
+func (o *Orchestrator) ReceiverName() string {
+	return o.definition.SagaName()
+}
+
 // ReceiveMessage implements msg.MessageReceiver.ReceiveMessage
 func (o *Orchestrator) ReceiveMessage(ctx context.Context, message msg.Message) error {
 	replyName, sagaID, sagaName, err := o.replyMessageInfo(message)
@@ -104,7 +200,7 @@ func (o *Orchestrator) ReceiveMessage(ctx context.Context, message msg.Message)
 
 	logger.Debug("received saga reply message")
 
-	reply, err := core.DeserializeReply(replyName, message.Payload())
+	reply, err := core.DeserializeReply(core.VersionedName(replyName, msg.SchemaVersion(message.Headers())), message.Payload())
 	if err != nil {
 		// sagas should not be receiving any replies that have not already been registered
 		logger.Error("error decoding reply message payload", zap.Error(err))
@@ -113,21 +209,148 @@ func (o *Orchestrator) ReceiveMessage(ctx context.Context, message msg.Message)
 
 	replyMsg := msg.NewReply(reply, message.Headers())
 
+	// the instance is found again and the reply handled again when another reply updated it concurrently
+	return o.retryConflicts(ctx, func(ctx context.Context) error {
+		return o.processReply(ctx, sagaName, sagaID, replyMsg)
+	})
+}
+
+// Resend executes the current step of an instance again
+func (o *Orchestrator) Resend(ctx context.Context, sagaID string) error {
+	return o.retryConflicts(ctx, func(ctx context.Context) error {
+		v, instance, err := o.findRunning(ctx, sagaID)
+		if err != nil {
+			return err
+		}
+
+		stepCtx := instance.getStepContext()
+
+		return v.processResults(ctx, instance, v.executeCurrentStep(ctx, stepCtx.retried(), instance.SagaData()), historyCause{event: HistoryResent})
+	})
+}
+
+// Compensate stops an instance and compensates it, starting with the current step
+func (o *Orchestrator) Compensate(ctx context.Context, sagaID string) error {
+	return o.retryConflicts(ctx, func(ctx context.Context) error {
+		v, instance, err := o.findRunning(ctx, sagaID)
+		if err != nil {
+			return err
+		}
+
+		if instance.compensating {
+			return ErrSagaCompensating
+		}
+
+		if v.pastPivot(instance.currentStep) {
+			return ErrSagaPastPivot
+		}
+
+		// the command of the current step may have been processed already
+		return v.processResults(ctx, instance, v.compensateCurrentStep(ctx, instance.getStepContext(), instance.SagaData()), historyCause{event: HistoryForcedCompensation})
+	})
+}
+
+// Complete ends an instance without executing the remaining steps
+func (o *Orchestrator) Complete(ctx context.Context, sagaID string) error {
+	return o.retryConflicts(ctx, func(ctx context.Context) error {
+		v, instance, err := o.findRunning(ctx, sagaID)
+		if err != nil {
+			return err
+		}
+
+		stepCtx := instance.getStepContext()
+
+		return v.processResults(ctx, instance, &stepResults{updatedStepContext: stepCtx.end()}, historyCause{event: HistoryForcedCompletion})
+	})
+}
+
+// SagaName returns the name of the saga the orchestrator executes
+func (o *Orchestrator) SagaName() string {
+	return o.definition.SagaName()
+}
+
+// findRunning returns the instance with the orchestrator of the definition version that started it
+func (o *Orchestrator) findRunning(ctx context.Context, sagaID string) (*Orchestrator, *Instance, error) {
+	instance, err := o.instanceStore.Find(ctx, o.definition.SagaName(), sagaID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if instance.endState {
+		return nil, nil, ErrSagaEnded
+	}
+
+	v, err := o.forInstance(instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return v, instance, nil
+}
+
+func (o *Orchestrator) processReply(ctx context.Context, sagaName, sagaID string, replyMsg msg.Reply) error {
+	logger := o.logger.With(
+		zap.String("SagaName", sagaName),
+		zap.String("SagaID", sagaID),
+	)
+
 	instance, err := o.instanceStore.Find(ctx, sagaName, sagaID)
 	if err != nil {
 		logger.Error("failed to locate saga instance data", zap.Error(err))
 		return nil
 	}
 
-	stepCtx := instance.getStepContext()
+	v, err := o.forInstance(instance)
+	if err != nil {
+		logger.Error("cannot process saga reply", zap.Error(err))
+		return nil
+	}
+
+	var results *stepResults
+	cause := historyCause{event: HistoryReplied, reply: replyMsg}
 
-	results, err := o.handleReply(ctx, stepCtx, instance.SagaData(), replyMsg)
+	switch {
+	case replyMsg.Headers().Has(MessageReplySagaTimeout):
+		cause.event = HistoryTimedOut
+		if instance.executionState.Retrying && instance.stepDeadline.Before(time.Now()) {
+			cause.event = HistoryRetried
+		}
+		results, err = v.handleTimeout(ctx, instance, replyMsg)
+	case staleReply(instance, replyMsg.Headers()):
+		// a redelivered reply, or a late reply to a step that has been sent again, must not move the current step
+		logger.Warn("dropping reply for a step the saga is no longer on",
+			zap.String("ReplyStep", replyMsg.Headers().Get(MessageReplySagaStep)),
+			zap.String("ReplyCompensating", replyMsg.Headers().Get(MessageReplySagaCompensating)),
+			zap.String("ReplySequence", replyMsg.Headers().Get(MessageReplySagaSequence)),
+			zap.Int("Step", instance.currentStep),
+			zap.Bool("Compensating", instance.compensating),
+			zap.Int("Sequence", instance.executionState.Sequence),
+			zap.Bool("EndState", instance.endState),
+		)
+		afterSession(ctx, func(context.Context) {
+			v.metrics.replyDropped(instance.sagaName)
+		})
+		return nil
+	case instance.executionState.Retrying:
+		// the step has failed already; the reply is for an attempt that is no longer current
+		logger.Info("ignoring reply while waiting to retry the step")
+		return nil
+	default:
+		results = v.retryStep(ctx, instance.getStepContext(), instance.SagaData(), replyMsg)
+		if results == nil {
+			results, err = v.handleReply(ctx, instance.getStepContext(), instance.SagaData(), replyMsg)
+		}
+	}
 	if err != nil {
 		logger.Error("saga reply handler returned an error", zap.Error(err))
 		return err
 	}
 
-	err = o.processResults(ctx, instance, results)
+	if results == nil {
+		return nil
+	}
+
+	err = v.processResults(ctx, instance, results, cause)
 	if err != nil {
 		logger.Error("error while processing results", zap.Error(err))
 		return err
@@ -161,7 +384,7 @@ func (o *Orchestrator) replyMessageInfo(message msg.Message) (string, string, st
 	return replyName, sagaID, sagaName, nil
 }
 
-func (o *Orchestrator) processResults(ctx context.Context, instance *Instance, results *stepResults) error {
+func (o *Orchestrator) processResults(ctx context.Context, instance *Instance, results *stepResults, cause historyCause) error {
 	var err error
 
 	logger := o.logger.With(
@@ -169,37 +392,102 @@ func (o *Orchestrator) processResults(ctx context.Context, instance *Instance, r
 		zap.String("SagaID", instance.sagaID),
 	)
 
-	for {
+	for results != nil {
 		if results.failure != nil {
 			logger.Info("handling local failure result")
+			cause = historyCause{event: HistoryLocalFailure, err: results.failure}
+			if retried := o.retryStep(ctx, results.updatedStepContext, results.updatedSagaData, msg.WithFailure()); retried != nil {
+				results = retried
+				continue
+			}
 			results, err = o.handleReply(ctx, results.updatedStepContext, results.updatedSagaData, msg.WithFailure())
 			if err != nil {
 				logger.Error("error handling local failure result", zap.Error(err))
 				return err
 			}
 		} else {
-			for _, command := range results.commands {
-				err = o.publisher.PublishCommand(ctx, o.definition.ReplyChannel(), command, WithSagaInfo(instance))
-				if err != nil {
-					return err
+			previous := instance.getStepContext()
+			instance.updateStepContext(results.updatedStepContext)
+			o.recordStep(ctx, instance, previous, cause)
+
+			if !results.waiting {
+				instance.correlationKey = results.correlationKey
+				instance.stepDeadline = time.Time{}
+				if results.timeout > 0 && !results.updatedStepContext.ended {
+					instance.stepDeadline = time.Now().Add(results.timeout)
 				}
 			}
 
-			instance.updateStepContext(results.updatedStepContext)
+			// the saga can no longer be compensated once it reaches the retriable steps, its deadline no longer applies
+			if o.retriable(results.updatedStepContext) {
+				instance.deadline = time.Time{}
+			}
 
 			if results.updatedSagaData != nil {
 				instance.sagaData = results.updatedSagaData
 			}
 
-			if results.updatedStepContext.ended {
-				o.processEnd(instance)
+			if results.subSaga != nil {
+				err = o.executeSubSaga(ctx, instance, results)
+				if err != nil {
+					logger.Error("error executing sub-saga", zap.Error(err))
+					return err
+				}
 			}
 
+			// replies to the commands sent before are stale once the step has been sent again
+			if len(results.commands) > 0 {
+				instance.executionState.Sequence++
+			}
+
+			// the instance is updated before the commands are published so a conflicting update publishes nothing
 			err = o.instanceStore.Update(ctx, instance)
 			if err != nil {
 				logger.Error("error saving saga instance", zap.Error(err))
 				return err
 			}
+			instance.version++
+
+			// the history is saved before the commands are published so a failed publish leaves no record without it
+			sent := make([]HistoryCommand, 0, len(results.commands))
+			for _, command := range results.commands {
+				sent = append(sent, HistoryCommand{CommandName: command.command.CommandName(), MessageID: uuid.New().String()})
+			}
+
+			err = o.appendHistory(ctx, instance, cause, sent)
+			if err != nil {
+				logger.Error("error saving saga history", zap.Error(err))
+				return err
+			}
+
+			traceHeaders := o.commandTraceHeaders(ctx, instance)
+			for i, command := range results.commands {
+				err = o.publisher.PublishCommand(ctx, o.definition.ReplyChannel(), command.command, WithSagaInfo(instance), msg.WithHeaders(traceHeaders), msg.WithHeaders(command.headers), msg.WithMessageID(sent[i].MessageID))
+				if err != nil {
+					return err
+				}
+			}
+
+			if results.updatedStepContext.ended {
+				afterSession(ctx, func(context.Context) {
+					o.processEnd(instance)
+				})
+
+				if instance.parentSagaID != "" {
+					err = o.notifyParent(ctx, instance)
+					if err != nil {
+						logger.Error("error replying to the parent saga", zap.Error(err))
+						return err
+					}
+				}
+
+				// waiting callers are woken; they find the instance again, so a lost notification only delays them
+				afterSession(ctx, func(ctx context.Context) {
+					if err := o.notifier.Notify(ctx, instance.sagaName, instance.sagaID); err != nil {
+						logger.Warn("error notifying that the saga has ended", zap.Error(err))
+					}
+				})
+			}
 
 			if !results.local {
 				logger.Info("exiting step loop")
@@ -208,6 +496,7 @@ func (o *Orchestrator) processResults(ctx context.Context, instance *Instance, r
 
 			// handle a local success outcome and kick off the next step
 			logger.Info("handling local success result")
+			cause = historyCause{event: HistoryLocalSuccess}
 			results, err = o.handleReply(ctx, results.updatedStepContext, results.updatedSagaData, msg.WithSuccess())
 			if err != nil {
 				logger.Error("error handling local success result", zap.Error(err))
@@ -225,6 +514,8 @@ func (o *Orchestrator) processEnd(instance *Instance) {
 		zap.String("SagaID", instance.sagaID),
 	)
 
+	o.metrics.sagaEnded(instance.sagaName, instance.compensating)
+
 	if instance.compensating {
 		logger.Info("executing saga compensated hook")
 		o.definition.OnHook(SagaCompensated, instance)
@@ -250,6 +541,10 @@ func (o *Orchestrator) handleReply(ctx context.Context, stepCtx stepContext, sag
 	}
 	step := o.definition.Steps()[stepCtx.step]
 
+	if parallelStep, ok := step.(ParallelStep); ok {
+		return o.handleParallelReply(ctx, parallelStep, stepCtx, sagaData, message)
+	}
+
 	// handle specific replies
 	if handler := step.getReplyHandler(replyName, stepCtx.compensating); handler != nil {
 		logger.Info("saga reply handler found")
@@ -271,6 +566,9 @@ func (o *Orchestrator) handleReply(ctx context.Context, stepCtx stepContext, sag
 	success := outcome == msg.ReplyOutcomeSuccess
 
 	switch {
+	case success && stepCtx.cancelled && !stepCtx.compensating:
+		logger.Info("saga was cancelled; compensating from the current step")
+		return o.compensateCurrentStep(ctx, stepCtx, sagaData), nil
 	case success:
 		logger.Info("advancing to next step")
 		return o.executeNextStep(ctx, stepCtx, sagaData), nil
@@ -284,6 +582,26 @@ func (o *Orchestrator) handleReply(ctx context.Context, stepCtx stepContext, sag
 	}
 }
 
+// compensateCurrentStep starts compensating with the current step instead of the previous one
+func (o *Orchestrator) compensateCurrentStep(ctx context.Context, stepCtx stepContext, sagaData core.SagaData) *stepResults {
+	// starting one step ahead includes the current step
+	stepCtx.step++
+	stepCtx.compensating = isCompensating
+
+	return o.executeNextStep(ctx, stepCtx, sagaData)
+}
+
+func (o *Orchestrator) executeCurrentStep(ctx context.Context, stepCtx stepContext, sagaData core.SagaData) *stepResults {
+	results := &stepResults{
+		updatedSagaData:    sagaData,
+		updatedStepContext: stepCtx,
+	}
+
+	o.definition.Steps()[stepCtx.step].execute(ctx, sagaData, stepCtx.compensating)(results)
+
+	return results
+}
+
 func (o *Orchestrator) executeNextStep(ctx context.Context, stepCtx stepContext, sagaData core.SagaData) *stepResults {
 	var stepDelta = 1
 	var direction = 1
@@ -1,6 +1,11 @@
 package saga
 
-import "github.com/nguyenta1993/service-kit/logger"
+import (
+	"time"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/retry"
+)
 
 // OrchestratorOption options for Orchestrator
 type OrchestratorOption func(o *Orchestrator)
@@ -11,3 +16,68 @@ func WithOrchestratorLogger(logger logger.Logger) OrchestratorOption {
 		o.logger = logger
 	}
 }
+
+// WithOrchestratorSagaTimeout is an option to set how long a saga may run before it is compensated
+func WithOrchestratorSagaTimeout(timeout time.Duration) OrchestratorOption {
+	return func(o *Orchestrator) {
+		o.sagaTimeout = timeout
+	}
+}
+
+// WithOrchestratorConflictRetryer is an option to set how conflicting updates are retried
+func WithOrchestratorConflictRetryer(retryer retry.Retryer) OrchestratorOption {
+	return func(o *Orchestrator) {
+		o.conflictRetryer = retryer
+	}
+}
+
+// WithOrchestratorSession is an option to run each attempt of an operation in a Session, e.g. pgx.NewSession
+func WithOrchestratorSession(session Session) OrchestratorOption {
+	return func(o *Orchestrator) {
+		if session != nil {
+			o.session = session
+		}
+	}
+}
+
+// WithOrchestratorHistoryStore is an option to record every transition of the saga instances in a HistoryStore
+func WithOrchestratorHistoryStore(store HistoryStore) OrchestratorOption {
+	return func(o *Orchestrator) {
+		o.historyStore = store
+	}
+}
+
+// WithOrchestratorRetriableBackoff is an option to set the delays of retriable steps without a backoff of their own
+func WithOrchestratorRetriableBackoff(backoff *retry.Backoff) OrchestratorOption {
+	return func(o *Orchestrator) {
+		o.retriableBackoff = backoff
+	}
+}
+
+// WithOrchestratorDefinitions is an option to keep executing the instances started by earlier versions of the definition
+func WithOrchestratorDefinitions(definitions ...Definition) OrchestratorOption {
+	return func(o *Orchestrator) {
+		o.previousDefinitions = append(o.previousDefinitions, definitions...)
+	}
+}
+
+// WithOrchestratorMetrics is an option to record the Prometheus Metrics of the sagas
+func WithOrchestratorMetrics(metrics *Metrics) OrchestratorOption {
+	return func(o *Orchestrator) {
+		o.metrics = metrics
+	}
+}
+
+// WithOrchestratorNotifier is an option to set the Notifier that wakes the callers of StartAndWait
+func WithOrchestratorNotifier(notifier Notifier) OrchestratorOption {
+	return func(o *Orchestrator) {
+		o.notifier = notifier
+	}
+}
+
+// WithOrchestratorWaitPollInterval is an option to set how often StartAndWait finds the instance regardless
+func WithOrchestratorWaitPollInterval(interval time.Duration) OrchestratorOption {
+	return func(o *Orchestrator) {
+		o.waitPollInterval = interval
+	}
+}
@@ -0,0 +1,196 @@
+package saga_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/core"
+	"github.com/nguyenta1993/service-kit/saga/memory"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+	_ "github.com/nguyenta1993/service-kit/saga/msgpack"
+	"github.com/nguyenta1993/service-kit/saga/saga"
+)
+
+type orderData struct{ OrderID string }
+
+func (orderData) SagaDataName() string { return "saga_test.orderData" }
+
+type orderCommand struct{ Channel string }
+
+func (orderCommand) CommandName() string          { return "saga_test.orderCommand" }
+func (c orderCommand) DestinationChannel() string { return c.Channel }
+
+func init() {
+	core.RegisterSagaData(orderData{})
+	core.RegisterCommands(orderCommand{})
+}
+
+// remoteStep returns a step sending an orderCommand to the channel
+func remoteStep(channel string) saga.RemoteStep {
+	return saga.NewRemoteStep().
+		Action(func(context.Context, core.SagaData) msg.DomainCommand {
+			return orderCommand{Channel: channel}
+		}).
+		NonCompensatable()
+}
+
+// replyTo returns a success reply echoing the saga headers of the command, as the command dispatchers do
+func replyTo(t *testing.T, command msg.Message) msg.Message {
+	t.Helper()
+
+	reply := successReply(t, command.Headers().Get(saga.MessageCommandSagaName), command.Headers().Get(saga.MessageCommandSagaID))
+	reply.Headers()[saga.MessageReplySagaStep] = command.Headers().Get(saga.MessageCommandSagaStep)
+	reply.Headers()[saga.MessageReplySagaCompensating] = command.Headers().Get(saga.MessageCommandSagaCompensating)
+	reply.Headers()[saga.MessageReplySagaSequence] = command.Headers().Get(saga.MessageCommandSagaSequence)
+
+	return reply
+}
+
+// conflictingStore fails the next Update with a conflict once armed, as if another reply updated the instance first
+type conflictingStore struct {
+	*memory.SagaInstanceStore
+	conflict bool
+}
+
+func (s *conflictingStore) Update(ctx context.Context, instance *saga.Instance) error {
+	if s.conflict {
+		s.conflict = false
+		return &saga.ErrInstanceConflict{SagaName: instance.SagaName(), SagaID: instance.SagaID(), Version: instance.Version()}
+	}
+
+	return s.SagaInstanceStore.Update(ctx, instance)
+}
+
+func TestOrchestrator_Session(t *testing.T) {
+	ctx := context.Background()
+	log := logger.NewDefaultLogger("error")
+
+	// the sessions the local step ran in
+	var ranIn []int
+
+	definition, err := saga.NewDefinition("saga_test.session", "session-replies").
+		Step(remoteStep("inventory")).
+		Step(saga.NewLocalStep(func(ctx context.Context, _ core.SagaData) error {
+			ranIn = append(ranIn, ctx.Value(sessionKey{}).(int))
+			return nil
+		})).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	var sessions []error
+	session := func(ctx context.Context, fn func(context.Context) error) error {
+		err := fn(context.WithValue(ctx, sessionKey{}, len(sessions)))
+		sessions = append(sessions, err)
+		return err
+	}
+
+	store := &conflictingStore{SagaInstanceStore: memory.NewSagaInstanceStore()}
+	broker := memory.NewBroker(log)
+	orchestrator := saga.NewOrchestrator(definition, store, msg.NewPublisher(broker.Producer(), log), log,
+		saga.WithOrchestratorSession(session),
+	)
+
+	started, err := orchestrator.Start(ctx, &orderData{OrderID: "order-id"})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	store.conflict = true
+	if err = orchestrator.ReceiveMessage(ctx, replyTo(t, broker.Published("inventory")[0])); err != nil {
+		t.Fatalf("ReceiveMessage() error = %v", err)
+	}
+
+	// the conflicting attempt fails its session so it is rolled back; the reply is handled again in a new one
+	if len(sessions) != 3 || sessions[0] != nil || sessions[1] == nil || sessions[2] != nil {
+		t.Fatalf("session results = %v, want [<nil> conflict <nil>]", sessions)
+	}
+	if len(ranIn) != 2 || ranIn[0] != 1 || ranIn[1] != 2 {
+		t.Errorf("local step ran in sessions %v, want [1 2]", ranIn)
+	}
+
+	instance, err := store.Find(ctx, started.SagaName(), started.SagaID())
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if !instance.EndState() {
+		t.Errorf("instance EndState = %v, want true", instance.EndState())
+	}
+}
+
+// countingNotifier counts the notifications sent by the orchestrator
+type countingNotifier struct {
+	*saga.LocalNotifier
+	notified int
+}
+
+func (n *countingNotifier) Notify(ctx context.Context, sagaName, sagaID string) error {
+	n.notified++
+
+	return n.LocalNotifier.Notify(ctx, sagaName, sagaID)
+}
+
+func TestOrchestrator_SessionEffects(t *testing.T) {
+	ctx := context.Background()
+	log := logger.NewDefaultLogger("error")
+
+	completed := 0
+
+	definition, err := saga.NewDefinition("saga_test.session_effects", "session-effects-replies").
+		Step(remoteStep("inventory")).
+		OnCompleted(func(*saga.Instance) {
+			completed++
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	errCommit := errors.New("commit failed")
+	var commitErr error
+	session := func(ctx context.Context, fn func(context.Context) error) error {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		return commitErr
+	}
+
+	notifier := &countingNotifier{LocalNotifier: saga.NewLocalNotifier()}
+	broker := memory.NewBroker(log)
+	orchestrator := saga.NewOrchestrator(definition, memory.NewSagaInstanceStore(), msg.NewPublisher(broker.Producer(), log), log,
+		saga.WithOrchestratorSession(session),
+		saga.WithOrchestratorNotifier(notifier),
+	)
+
+	if _, err = orchestrator.Start(ctx, &orderData{OrderID: "order-id"}); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	// the session that ended the instance is rolled back; its hook and notification must not run
+	commitErr = errCommit
+	if err = orchestrator.ReceiveMessage(ctx, replyTo(t, broker.Published("inventory")[0])); !errors.Is(err, errCommit) {
+		t.Fatalf("ReceiveMessage() error = %v, want %v", err, errCommit)
+	}
+	if completed != 0 || notifier.notified != 0 {
+		t.Errorf("completed hooks = %d, notifications = %d after a rollback, want 0 and 0", completed, notifier.notified)
+	}
+}
+
+func successReply(t *testing.T, sagaName, sagaID string) msg.Message {
+	t.Helper()
+
+	payload, err := core.SerializeReply(msg.Success{})
+	if err != nil {
+		t.Fatalf("SerializeReply() error = %v", err)
+	}
+
+	return msg.NewMessage(payload, msg.WithHeaders(msg.Headers{
+		msg.MessageReplyName:      msg.Success{}.ReplyName(),
+		msg.MessageReplyOutcome:   msg.ReplyOutcomeSuccess,
+		saga.MessageReplySagaName: sagaName,
+		saga.MessageReplySagaID:   sagaID,
+	}))
+}
@@ -0,0 +1,249 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/nguyenta1993/service-kit/saga/core"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+)
+
+// ParallelStep is used to execute several remote steps at once
+type ParallelStep struct {
+	branches []RemoteStep
+}
+
+var _ Step = (*ParallelStep)(nil)
+
+// NewParallelStep constructor for ParallelStep
+func NewParallelStep(branches ...RemoteStep) ParallelStep {
+	return ParallelStep{
+		branches: branches,
+	}
+}
+
+func (s ParallelStep) hasInvocableAction(ctx context.Context, sagaData core.SagaData, compensating bool) bool {
+	for _, branch := range s.branches {
+		if branch.hasInvocableAction(ctx, sagaData, compensating) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getReplyHandler is not used; replies are handled by the branch named in the reply
+func (s ParallelStep) getReplyHandler(string, bool) func(context.Context, core.SagaData, core.Reply) error {
+	return nil
+}
+
+func (s ParallelStep) execute(ctx context.Context, sagaData core.SagaData, compensating bool) func(results *stepResults) {
+	return func(results *stepResults) {
+		stepCtx := results.updatedStepContext
+		recorded := stepCtx.branches[stepCtx.step]
+
+		states := make([]BranchState, len(s.branches))
+		copy(states, recorded)
+
+		for i, branch := range s.branches {
+			if !s.shouldExecute(states[i], recorded != nil, compensating) {
+				continue
+			}
+
+			if !branch.hasInvocableAction(ctx, sagaData, compensating) {
+				states[i] = s.resolvedState(BranchSkipped, BranchCompensated, compensating)
+				continue
+			}
+
+			action := branch.actionHandlers[compensating]
+
+			command := action.execute(ctx, sagaData)
+			if command == nil {
+				states[i] = s.resolvedState(BranchSucceeded, BranchCompensated, compensating)
+				continue
+			}
+
+			results.commands = append(results.commands, stepCommand{
+				command: command,
+				headers: msg.Headers{MessageCommandSagaBranch: strconv.Itoa(i)},
+			})
+			states[i] = s.resolvedState(BranchPending, BranchCompensating, compensating)
+
+			if action.timeout > results.timeout {
+				results.timeout = action.timeout
+			}
+		}
+
+		results.updatedStepContext = stepCtx.withBranches(states)
+		// nothing was sent; the step is done
+		results.local = len(results.commands) == 0
+	}
+}
+
+func (s ParallelStep) validate() error {
+	if len(s.branches) == 0 {
+		return fmt.Errorf("parallel step has no branches")
+	}
+
+	for i, branch := range s.branches {
+		if err := branch.validate(); err != nil {
+			return fmt.Errorf("branch %d: %w", i, err)
+		}
+		if action := branch.actionHandlers[notCompensating]; action != nil && action.retryBackoff != nil {
+			return fmt.Errorf("branch %d: retries are not supported in parallel steps", i)
+		}
+		if branch.kind() != compensatableStep {
+			return fmt.Errorf("branch %d: a %s step cannot be a branch of a parallel step", i, branch.kind())
+		}
+	}
+
+	return nil
+}
+
+// kind is always compensatable; branches cannot be pivots or retriable
+func (s ParallelStep) kind() stepKind {
+	return compensatableStep
+}
+
+// shouldExecute returns whether or not a branch in the state needs its action, or compensation, sent
+func (s ParallelStep) shouldExecute(state BranchState, recorded bool, compensating bool) bool {
+	if !compensating {
+		return state == "" || state == BranchPending
+	}
+
+	switch state {
+	case BranchSucceeded, BranchPending, BranchCompensating:
+		return true
+	case "":
+		// without recorded states, every branch with a compensation is compensated
+		return !recorded
+	default:
+		return false
+	}
+}
+
+func (s ParallelStep) resolvedState(state, compensatingState BranchState, compensating bool) BranchState {
+	if compensating {
+		return compensatingState
+	}
+
+	return state
+}
+
+func (s ParallelStep) branch(headers msg.Headers) (int, bool, error) {
+	value := headers.Get(MessageReplySagaBranch)
+	if value == "" {
+		return 0, false, nil
+	}
+
+	index, err := strconv.Atoi(value)
+	if err != nil || index < 0 || index >= len(s.branches) {
+		return 0, false, fmt.Errorf("reply branch is out of bounds: 0-%d, got %s", len(s.branches), value)
+	}
+
+	return index, true, nil
+}
+
+// handleParallelReply records the outcome of a branch and advances, or compensates, once no branch is waiting
+func (o *Orchestrator) handleParallelReply(ctx context.Context, step ParallelStep, stepCtx stepContext, sagaData core.SagaData, message msg.Reply) (*stepResults, error) {
+	replyName := message.Reply().ReplyName()
+
+	logger := o.logger.With(
+		zap.String("SagaName", o.definition.SagaName()),
+		zap.String("SagaID", message.Headers().Get(MessageReplySagaID)),
+		zap.String("ReplyName", replyName),
+		zap.Int("Step", stepCtx.step),
+	)
+
+	outcome, err := message.Headers().GetRequired(msg.MessageReplyOutcome)
+	if err != nil {
+		logger.Error("error reading reply outcome", zap.Error(err))
+		return nil, err
+	}
+
+	success := outcome == msg.ReplyOutcomeSuccess
+
+	waitingState := BranchPending
+	if stepCtx.compensating {
+		waitingState = BranchCompensating
+	}
+
+	states := make([]BranchState, len(step.branches))
+	copy(states, stepCtx.branches[stepCtx.step])
+
+	index, hasBranch, err := step.branch(message.Headers())
+	if err != nil {
+		logger.Error("error reading reply branch", zap.Error(err))
+		return nil, err
+	}
+
+	var replied []int
+	switch {
+	case hasBranch && states[index] != waitingState:
+		logger.Info("ignoring reply for a branch that is not waiting", zap.Int("Branch", index), zap.String("BranchState", string(states[index])))
+		return nil, nil
+	case hasBranch:
+		replied = append(replied, index)
+	default:
+		for i, state := range states {
+			if state == waitingState {
+				replied = append(replied, i)
+			}
+		}
+		if success && len(replied) > 0 {
+			logger.Warn("ignoring success outcome without a branch while branches are waiting")
+			return nil, nil
+		}
+	}
+
+	for _, i := range replied {
+		if hasBranch {
+			if handler := step.branches[i].getReplyHandler(replyName, stepCtx.compensating); handler != nil {
+				logger.Info("saga reply handler found", zap.Int("Branch", i))
+				err := handler(ctx, sagaData, message.Reply())
+				if err != nil {
+					logger.Error("saga reply handler returned an error", zap.Error(err))
+					return nil, err
+				}
+			}
+		}
+
+		switch {
+		case stepCtx.compensating && !success:
+			logger.Error("received a failure outcome while compensating", zap.Int("Branch", i))
+			return nil, fmt.Errorf("received failure outcome while compensating")
+		case stepCtx.compensating:
+			states[i] = BranchCompensated
+		case success:
+			states[i] = BranchSucceeded
+		default:
+			states[i] = BranchFailed
+		}
+
+		logger.Info("branch reply outcome", zap.Int("Branch", i), zap.String("Outcome", outcome))
+	}
+
+	stepCtx = stepCtx.withBranches(states)
+
+	failed := false
+	for _, state := range states {
+		switch state {
+		case waitingState:
+			logger.Info("waiting for the remaining branches")
+			return &stepResults{updatedSagaData: sagaData, updatedStepContext: stepCtx, waiting: true}, nil
+		case BranchFailed:
+			failed = true
+		}
+	}
+
+	if (failed || stepCtx.cancelled) && !stepCtx.compensating {
+		logger.Info("compensating the branches that succeeded")
+		return o.executeCurrentStep(ctx, stepCtx.compensate(), sagaData), nil
+	}
+
+	logger.Info("advancing to next step")
+	return o.executeNextStep(ctx, stepCtx, sagaData), nil
+}
@@ -0,0 +1,10 @@
+package saga
+
+import (
+	"github.com/nguyenta1993/service-kit/saga/core"
+)
+
+// RegisterTypes should be called after registering a new marshaller; especially after registering a new default
+func RegisterTypes() {
+	core.RegisterReplies(SubSagaEnded{})
+}
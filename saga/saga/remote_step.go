@@ -2,6 +2,7 @@ package saga
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/nguyenta1993/service-kit/saga/core"
 	"github.com/nguyenta1993/service-kit/saga/msg"
@@ -9,8 +10,10 @@ import (
 
 // RemoteStep is used to execute distributed saga business logic
 type RemoteStep struct {
-	actionHandlers map[bool]*remoteStepAction
-	replyHandlers  map[bool]map[string]func(context.Context, core.SagaData, core.Reply) error
+	actionHandlers   map[bool]*remoteStepAction
+	replyHandlers    map[bool]map[string]func(context.Context, core.SagaData, core.Reply) error
+	nonCompensatable bool
+	stepKind         stepKind
 }
 
 var _ Step = (*RemoteStep)(nil)
@@ -77,6 +80,27 @@ func (s RemoteStep) HandleCompensationReply(reply core.Reply, handler func(conte
 	return s
 }
 
+// NonCompensatable marks a step with an action as intentionally having no compensation
+func (s RemoteStep) NonCompensatable() RemoteStep {
+	s.nonCompensatable = true
+
+	return s
+}
+
+// Pivot marks the step as the point of no return; once it succeeds the saga is no longer compensated
+func (s RemoteStep) Pivot() RemoteStep {
+	s.stepKind = pivotStep
+
+	return s
+}
+
+// Retriable marks the step as one that will eventually succeed; a failed action is sent again until it does
+func (s RemoteStep) Retriable() RemoteStep {
+	s.stepKind = retriableStep
+
+	return s
+}
+
 func (s RemoteStep) hasInvocableAction(ctx context.Context, sagaData core.SagaData, compensating bool) bool {
 	return s.actionHandlers[compensating] != nil && s.actionHandlers[compensating].isInvocable(ctx, sagaData)
 }
@@ -86,11 +110,49 @@ func (s RemoteStep) getReplyHandler(replyName string, compensating bool) func(co
 }
 
 func (s RemoteStep) execute(ctx context.Context, sagaData core.SagaData, compensating bool) func(results *stepResults) {
-	if commandToSend := s.actionHandlers[compensating].execute(ctx, sagaData); commandToSend != nil {
+	action := s.actionHandlers[compensating]
+
+	if commandToSend := action.execute(ctx, sagaData); commandToSend != nil {
 		return func(actions *stepResults) {
-			actions.commands = []msg.DomainCommand{commandToSend}
+			actions.commands = []stepCommand{{command: commandToSend}}
+			actions.timeout = action.timeout
 		}
 	}
 
 	return func(actions *stepResults) {}
 }
+
+func (s RemoteStep) validate() error {
+	action, compensation := s.actionHandlers[notCompensating], s.actionHandlers[isCompensating]
+
+	if action == nil && compensation == nil {
+		return fmt.Errorf("remote step has neither an action nor a compensation")
+	}
+
+	if s.stepKind != compensatableStep {
+		if action == nil {
+			return fmt.Errorf("%s remote step has no action", s.stepKind)
+		}
+		if compensation != nil {
+			return fmt.Errorf("%s remote step has a compensation; it is never compensated", s.stepKind)
+		}
+	}
+
+	if action != nil && compensation == nil && !s.nonCompensatable && s.stepKind == compensatableStep {
+		return fmt.Errorf("remote step has an action without a compensation; mark it with NonCompensatable() if that is intended")
+	}
+
+	for _, compensating := range []bool{notCompensating, isCompensating} {
+		for replyName := range s.replyHandlers[compensating] {
+			if !core.IsRegistered(replyName) {
+				return fmt.Errorf("reply `%s` is handled but has not been registered with core.RegisterReplies", replyName)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s RemoteStep) kind() stepKind {
+	return s.stepKind
+}
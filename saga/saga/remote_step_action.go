@@ -2,14 +2,20 @@ package saga
 
 import (
 	"context"
+	"time"
 
 	"github.com/nguyenta1993/service-kit/saga/core"
 	"github.com/nguyenta1993/service-kit/saga/msg"
+	"github.com/nguyenta1993/service-kit/saga/retry"
 )
 
 type remoteStepAction struct {
 	predicate func(context.Context, core.SagaData) bool
 	handler   func(context.Context, core.SagaData) msg.DomainCommand
+	timeout   time.Duration
+	// retryBackoff and isRetryable are set by WithRetry
+	retryBackoff *retry.Backoff
+	isRetryable  func(core.Reply) bool
 }
 
 func (a *remoteStepAction) isInvocable(ctx context.Context, sagaData core.SagaData) bool {
@@ -2,8 +2,10 @@ package saga
 
 import (
 	"context"
+	"time"
 
 	"github.com/nguyenta1993/service-kit/saga/core"
+	"github.com/nguyenta1993/service-kit/saga/retry"
 )
 
 // RemoteStepActionOption options for remoteStepAction
@@ -15,3 +17,18 @@ func WithRemoteStepPredicate(predicate func(context.Context, core.SagaData) bool
 		step.predicate = predicate
 	}
 }
+
+// WithRemoteStepTimeout sets how long the action will wait for a reply before it is treated as a failure
+func WithRemoteStepTimeout(timeout time.Duration) RemoteStepActionOption {
+	return func(step *remoteStepAction) {
+		step.timeout = timeout
+	}
+}
+
+// WithRetry sends the action again after the backoff delay when it fails, unless isRetryable returns false for the reply
+func WithRetry(backoff *retry.Backoff, isRetryable func(core.Reply) bool) RemoteStepActionOption {
+	return func(step *remoteStepAction) {
+		step.retryBackoff = backoff
+		step.isRetryable = isRetryable
+	}
+}
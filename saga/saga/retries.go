@@ -0,0 +1,99 @@
+package saga
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nguyenta1993/service-kit/saga/core"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+	"github.com/nguyenta1993/service-kit/saga/retry"
+)
+
+// retryStep waits to send a failed action again; nil is returned when the failure is not retried
+func (o *Orchestrator) retryStep(ctx context.Context, stepCtx stepContext, sagaData core.SagaData, message msg.Reply) *stepResults {
+	if stepCtx.compensating || stepCtx.cancelled || stepCtx.step < 0 || stepCtx.step >= len(o.definition.Steps()) {
+		return nil
+	}
+
+	if message.Headers().Get(msg.MessageReplyOutcome) != msg.ReplyOutcomeFailure {
+		return nil
+	}
+
+	step := o.definition.Steps()[stepCtx.step]
+
+	var backoff *retry.Backoff
+	var isRetryable func(core.Reply) bool
+	if remoteStep, ok := step.(RemoteStep); ok {
+		if action := remoteStep.actionHandlers[notCompensating]; action != nil {
+			backoff, isRetryable = action.retryBackoff, action.isRetryable
+		}
+	}
+
+	logger := o.logger.With(
+		zap.String("SagaName", o.definition.SagaName()),
+		zap.String("SagaID", message.Headers().Get(MessageReplySagaID)),
+		zap.Int("Step", stepCtx.step),
+		zap.Int("Attempts", stepCtx.attempts+1),
+	)
+
+	var delay time.Duration
+	switch {
+	case step.kind() == retriableStep:
+		if backoff == nil {
+			backoff = o.retriableBackoff
+		}
+		delay = backoff.Interval(stepCtx.attempts + 1)
+	case backoff == nil:
+		return nil
+	case isRetryable != nil && !isRetryable(message.Reply()):
+		logger.Info("step failure is not retryable")
+		return nil
+	default:
+		var ok bool
+		var elapsed time.Duration
+		if !stepCtx.failedAt.IsZero() {
+			elapsed = time.Since(stepCtx.failedAt)
+		}
+		if delay, ok = backoff.Delay(stepCtx.attempts+1, elapsed); !ok {
+			logger.Info("step retries are exhausted")
+			return nil
+		}
+	}
+
+	stepCtx = stepCtx.retry()
+
+	// local actions are always run again by the TimeoutScheduler so a failing action cannot loop
+	if _, local := step.(LocalStep); local && delay <= 0 {
+		delay = time.Millisecond
+	}
+
+	if delay <= 0 {
+		logger.Info("sending the failed step again")
+		return o.executeCurrentStep(ctx, stepCtx.retried(), sagaData)
+	}
+
+	logger.Info("waiting to send the failed step again", zap.Duration("Delay", delay))
+
+	return &stepResults{updatedSagaData: sagaData, updatedStepContext: stepCtx, timeout: delay}
+}
+
+// pastPivot returns whether or not the step is the pivot step or a retriable step
+func (o *Orchestrator) pastPivot(step int) bool {
+	if steps := o.definition.Steps(); step >= 0 && step < len(steps) {
+		kind := steps[step].kind()
+		return kind == pivotStep || kind == retriableStep
+	}
+
+	return false
+}
+
+// retriable returns whether or not the saga is executing its retriable steps and can no longer be compensated
+func (o *Orchestrator) retriable(stepCtx stepContext) bool {
+	if stepCtx.compensating || stepCtx.step < 0 || stepCtx.step >= len(o.definition.Steps()) {
+		return false
+	}
+
+	return o.definition.Steps()[stepCtx.step].kind() == retriableStep
+}
@@ -0,0 +1,79 @@
+package saga
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"github.com/nguyenta1993/service-kit/saga/retry"
+)
+
+// Session runs fn in a unit of work, e.g. a transaction, that is committed when fn succeeds
+type Session func(ctx context.Context, fn func(context.Context) error) error
+
+// noSession runs fn without a unit of work; for stores that do not use one, e.g. the memory store
+func noSession(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+type sessionEffectsKey struct{}
+
+// sessionEffects are run once the session of the attempt has committed
+type sessionEffects struct {
+	effects []func(context.Context)
+}
+
+// inSession runs fn in a session and then the effects it deferred with afterSession
+func (o *Orchestrator) inSession(ctx context.Context, fn func(context.Context) error) error {
+	outer, nested := ctx.Value(sessionEffectsKey{}).(*sessionEffects)
+
+	attempt := &sessionEffects{}
+	if err := o.session(context.WithValue(ctx, sessionEffectsKey{}, attempt), fn); err != nil {
+		return err
+	}
+
+	if nested {
+		outer.effects = append(outer.effects, attempt.effects...)
+		return nil
+	}
+
+	// the context of the attempt may carry its committed transaction
+	for _, effect := range attempt.effects {
+		effect(ctx)
+	}
+
+	return nil
+}
+
+// afterSession defers the effect until the session of the attempt has committed
+func afterSession(ctx context.Context, effect func(context.Context)) {
+	if attempt, ok := ctx.Value(sessionEffectsKey{}).(*sessionEffects); ok {
+		attempt.effects = append(attempt.effects, effect)
+		return
+	}
+
+	effect(ctx)
+}
+
+// retryConflicts calls fn again, each time in a session of its own, while it fails with an *ErrInstanceConflict
+func (o *Orchestrator) retryConflicts(ctx context.Context, fn func(context.Context) error) error {
+	return o.conflictRetryer.Retry(ctx, func() error {
+		err := o.inSession(ctx, fn)
+		if err == nil {
+			return nil
+		}
+
+		var conflict *ErrInstanceConflict
+		if errors.As(err, &conflict) {
+			o.logger.Warn("saga instance was updated concurrently; trying again",
+				zap.String("SagaName", conflict.SagaName),
+				zap.String("SagaID", conflict.SagaID),
+				zap.Int("Version", conflict.Version),
+			)
+			return err
+		}
+
+		return retry.DoNotRetry(err)
+	})
+}
@@ -0,0 +1,23 @@
+package saga
+
+import (
+	"strconv"
+
+	"github.com/nguyenta1993/service-kit/saga/msg"
+)
+
+// staleReply returns whether the reply is for a step, direction or send the instance is no longer on
+func staleReply(instance *Instance, headers msg.Headers) bool {
+	step := headers.Get(MessageReplySagaStep)
+	if step == "" {
+		return false
+	}
+
+	if sequence := headers.Get(MessageReplySagaSequence); sequence != "" && sequence != strconv.Itoa(instance.executionState.Sequence) {
+		return true
+	}
+
+	return instance.endState ||
+		step != strconv.Itoa(instance.currentStep) ||
+		headers.Get(MessageReplySagaCompensating) != strconv.FormatBool(instance.compensating)
+}
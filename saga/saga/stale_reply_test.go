@@ -0,0 +1,135 @@
+package saga_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/core"
+	"github.com/nguyenta1993/service-kit/saga/memory"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+	"github.com/nguyenta1993/service-kit/saga/saga"
+)
+
+func TestOrchestrator_StaleReplies(t *testing.T) {
+	ctx := context.Background()
+	log := logger.NewDefaultLogger("error")
+
+	definition, err := saga.NewDefinition("saga_test.redelivered", "redelivered-replies").
+		Step(remoteStep("inventory"), remoteStep("payment"), remoteStep("shipping")).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	store := memory.NewSagaInstanceStore()
+	broker := memory.NewBroker(log)
+	orchestrator := saga.NewOrchestrator(definition, store, msg.NewPublisher(broker.Producer(), log), log)
+
+	started, err := orchestrator.Start(ctx, &orderData{OrderID: "order-id"})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	inventoryReply := replyTo(t, broker.Published("inventory")[0])
+	for i := 0; i < 2; i++ {
+		if err = orchestrator.ReceiveMessage(ctx, inventoryReply); err != nil {
+			t.Fatalf("ReceiveMessage() error = %v", err)
+		}
+	}
+
+	if published := broker.Published("shipping"); len(published) != 0 {
+		t.Errorf("the redelivered reply advanced the saga; published %d shipping commands", len(published))
+	}
+
+	instance, err := store.Find(ctx, started.SagaName(), started.SagaID())
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if instance.CurrentStep() != 1 || instance.Compensating() {
+		t.Errorf("instance step = %d, compensating = %v; want step 1", instance.CurrentStep(), instance.Compensating())
+	}
+
+	// the payment step is sent again; the late reply to the first send must not be applied to the second
+	if err = orchestrator.Resend(ctx, started.SagaID()); err != nil {
+		t.Fatalf("Resend() error = %v", err)
+	}
+
+	payments := broker.Published("payment")
+	if len(payments) != 2 {
+		t.Fatalf("published %d payment commands, want 2", len(payments))
+	}
+
+	if err = orchestrator.ReceiveMessage(ctx, replyTo(t, payments[0])); err != nil {
+		t.Fatalf("ReceiveMessage() error = %v", err)
+	}
+
+	if published := broker.Published("shipping"); len(published) != 0 {
+		t.Errorf("the reply to the first send advanced the saga; published %d shipping commands", len(published))
+	}
+
+	if err = orchestrator.ReceiveMessage(ctx, replyTo(t, payments[1])); err != nil {
+		t.Fatalf("ReceiveMessage() error = %v", err)
+	}
+
+	if published := broker.Published("shipping"); len(published) != 1 {
+		t.Errorf("the reply to the second send did not advance the saga; published %d shipping commands", len(published))
+	}
+}
+
+func TestOrchestrator_StaleSubSagaReplies(t *testing.T) {
+	ctx := context.Background()
+	log := logger.NewDefaultLogger("error")
+
+	childDefinition, err := saga.NewDefinition("saga_test.child", "child-replies").Step(remoteStep("payment")).Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	store := memory.NewSagaInstanceStore()
+	broker := memory.NewBroker(log)
+	publisher := msg.NewPublisher(broker.Producer(), log)
+	child := saga.NewOrchestrator(childDefinition, store, publisher, log)
+
+	definition, err := saga.NewDefinition("saga_test.parent", "parent-replies").
+		Step(saga.NewSubSagaStep(child, func(_ context.Context, data core.SagaData) core.SagaData {
+			return data
+		}), remoteStep("shipping")).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	parent := saga.NewOrchestrator(definition, store, publisher, log)
+
+	started, err := parent.Start(ctx, &orderData{OrderID: "order-id"})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	if err = child.ReceiveMessage(ctx, replyTo(t, broker.Published("payment")[0])); err != nil {
+		t.Fatalf("ReceiveMessage() error = %v", err)
+	}
+
+	// the child echoes the step of the parent; a redelivered result must not be applied to the shipping step
+	ended := broker.Published("parent-replies")
+	if len(ended) != 1 || ended[0].Headers().Get(saga.MessageReplySagaStep) != "0" {
+		t.Fatalf("child replies = %v, want one for parent step 0", ended)
+	}
+	for i := 0; i < 2; i++ {
+		if err = parent.ReceiveMessage(ctx, ended[0]); err != nil {
+			t.Fatalf("ReceiveMessage() error = %v", err)
+		}
+	}
+
+	instance, err := store.Find(ctx, started.SagaName(), started.SagaID())
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if instance.CurrentStep() != 1 || instance.EndState() {
+		t.Errorf("instance step = %d, ended = %v; want step 1 running", instance.CurrentStep(), instance.EndState())
+	}
+	if published := broker.Published("shipping"); len(published) != 1 {
+		t.Errorf("published %d shipping commands, want 1", len(published))
+	}
+}
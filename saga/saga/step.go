@@ -11,4 +11,26 @@ type Step interface {
 	hasInvocableAction(ctx context.Context, sagaData core.SagaData, compensating bool) bool
 	getReplyHandler(replyName string, compensating bool) func(ctx context.Context, data core.SagaData, reply core.Reply) error
 	execute(ctx context.Context, sagaData core.SagaData, compensating bool) func(results *stepResults)
+	validate() error
+	kind() stepKind
+}
+
+// stepKind is the role of a step in a saga; compensatable steps come first, then an optional pivot, then retriable steps
+type stepKind int
+
+const (
+	compensatableStep stepKind = iota
+	pivotStep
+	retriableStep
+)
+
+func (k stepKind) String() string {
+	switch k {
+	case pivotStep:
+		return "pivot"
+	case retriableStep:
+		return "retriable"
+	default:
+		return "compensatable"
+	}
 }
@@ -1,23 +1,60 @@
 package saga
 
+import (
+	"time"
+)
+
 type stepContext struct {
 	step         int
 	compensating bool
 	ended        bool
+	// branches are the branch states of parallel steps by step index; it is replaced, never modified
+	branches map[int][]BranchState
+	// attempts, failedAt and retrying belong to the current step; moving to another step resets them
+	attempts int
+	failedAt time.Time
+	retrying bool
+	// cancelled is kept until the saga ends
+	cancelled bool
 }
 
 func (s *stepContext) next(stepIndex int) stepContext {
 	if s.compensating {
-		return stepContext{step: s.step - stepIndex, compensating: s.compensating}
+		return stepContext{step: s.step - stepIndex, compensating: s.compensating, branches: s.branches, cancelled: s.cancelled}
 	}
 
-	return stepContext{step: s.step + stepIndex, compensating: s.compensating}
+	return stepContext{step: s.step + stepIndex, compensating: s.compensating, branches: s.branches, cancelled: s.cancelled}
 }
 
 func (s *stepContext) compensate() stepContext {
-	return stepContext{step: s.step, compensating: true, ended: s.ended}
+	return stepContext{step: s.step, compensating: true, ended: s.ended, branches: s.branches, cancelled: s.cancelled}
 }
 
 func (s *stepContext) end() stepContext {
-	return stepContext{step: s.step, compensating: s.compensating, ended: true}
+	return stepContext{step: s.step, compensating: s.compensating, ended: true, branches: s.branches, cancelled: s.cancelled}
+}
+
+func (s *stepContext) withBranches(states []BranchState) stepContext {
+	branches := make(map[int][]BranchState, len(s.branches)+1)
+	for step, stepStates := range s.branches {
+		branches[step] = stepStates
+	}
+	branches[s.step] = states
+
+	return stepContext{step: s.step, compensating: s.compensating, ended: s.ended, branches: branches, attempts: s.attempts, failedAt: s.failedAt, retrying: s.retrying, cancelled: s.cancelled}
+}
+
+// retry counts a failed attempt of the current step and waits to send it again
+func (s *stepContext) retry() stepContext {
+	failedAt := s.failedAt
+	if failedAt.IsZero() {
+		failedAt = time.Now()
+	}
+
+	return stepContext{step: s.step, compensating: s.compensating, branches: s.branches, attempts: s.attempts + 1, failedAt: failedAt, retrying: true, cancelled: s.cancelled}
+}
+
+// retried stops waiting; the current step is being sent again
+func (s *stepContext) retried() stepContext {
+	return stepContext{step: s.step, compensating: s.compensating, branches: s.branches, attempts: s.attempts, failedAt: s.failedAt, cancelled: s.cancelled}
 }
@@ -1,14 +1,28 @@
 package saga
 
 import (
+	"time"
+
 	"github.com/nguyenta1993/service-kit/saga/core"
 	"github.com/nguyenta1993/service-kit/saga/msg"
 )
 
 type stepResults struct {
-	commands           []msg.DomainCommand
+	commands           []stepCommand
 	updatedSagaData    core.SagaData
 	updatedStepContext stepContext
+	timeout            time.Duration
 	local              bool
 	failure            error
+	// waiting is set when the step is still waiting for replies and its deadline is kept
+	waiting bool
+	// correlationKey is set when the step waits for an event
+	correlationKey string
+	// subSaga is set when the step starts, or compensates, a child saga
+	subSaga *subSagaResult
+}
+
+type stepCommand struct {
+	command msg.DomainCommand
+	headers msg.Headers
 }
@@ -0,0 +1,271 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/nguyenta1993/service-kit/saga/core"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+)
+
+// SubSagaStep is used to execute the saga of another Orchestrator as a step
+type SubSagaStep struct {
+	orchestrator *Orchestrator
+	data         func(context.Context, core.SagaData) core.SagaData
+	handler      func(context.Context, core.SagaData, core.SagaData) error
+}
+
+// SubSagaEnded is the reply a child saga sends to its parent once it has ended
+type SubSagaEnded struct {
+	SagaName    string
+	SagaID      string
+	Compensated bool
+}
+
+// ReplyName implements core.Reply.ReplyName
+func (SubSagaEnded) ReplyName() string { return "saga.SubSagaEnded" }
+
+var _ Step = (*SubSagaStep)(nil)
+
+// NewSubSagaStep constructor for SubSagaStep; data returns the saga data the child saga is started with
+func NewSubSagaStep(orchestrator *Orchestrator, data func(context.Context, core.SagaData) core.SagaData) SubSagaStep {
+	return SubSagaStep{
+		orchestrator: orchestrator,
+		data:         data,
+	}
+}
+
+// Handle sets the handler that is called with the saga data of the completed child saga
+func (s SubSagaStep) Handle(handler func(ctx context.Context, sagaData, childData core.SagaData) error) SubSagaStep {
+	s.handler = handler
+	return s
+}
+
+// SagaName returns the name of the child saga
+func (s SubSagaStep) SagaName() string {
+	return s.orchestrator.SagaName()
+}
+
+func (s SubSagaStep) hasInvocableAction(context.Context, core.SagaData, bool) bool {
+	return true
+}
+
+func (s SubSagaStep) getReplyHandler(replyName string, compensating bool) func(context.Context, core.SagaData, core.Reply) error {
+	if s.handler == nil || compensating || replyName != (SubSagaEnded{}).ReplyName() {
+		return nil
+	}
+
+	return func(ctx context.Context, sagaData core.SagaData, reply core.Reply) error {
+		ended, ok := reply.(*SubSagaEnded)
+		if !ok || ended.Compensated {
+			return nil
+		}
+
+		child, err := s.orchestrator.instanceStore.Find(ctx, ended.SagaName, ended.SagaID)
+		if err != nil {
+			return err
+		}
+
+		return s.handler(ctx, sagaData, child.SagaData())
+	}
+}
+
+func (s SubSagaStep) execute(ctx context.Context, sagaData core.SagaData, compensating bool) func(results *stepResults) {
+	var childData core.SagaData
+	if !compensating {
+		childData = s.data(ctx, sagaData)
+	}
+
+	return func(results *stepResults) {
+		results.subSaga = &subSagaResult{
+			step:         s,
+			sagaData:     childData,
+			compensating: compensating,
+		}
+	}
+}
+
+func (s SubSagaStep) validate() error {
+	if s.orchestrator == nil || s.data == nil {
+		return fmt.Errorf("sub-saga step requires an orchestrator and saga data")
+	}
+
+	// the child is compensated when the parent compensates; it must not be able to reach a pivot
+	for i, step := range s.orchestrator.definition.Steps() {
+		if step.kind() != compensatableStep {
+			return fmt.Errorf("sub-saga `%s` step %d is a %s step; child sagas must be compensatable", s.SagaName(), i, step.kind())
+		}
+	}
+
+	return nil
+}
+
+func (s SubSagaStep) kind() stepKind {
+	return compensatableStep
+}
+
+// subSagaResult is the child saga a step starts, or compensates, once the parent has been saved
+type subSagaResult struct {
+	step         SubSagaStep
+	sagaData     core.SagaData
+	compensating bool
+}
+
+// subSagaID returns the ID of the child saga started by a step of the parent; the same step starts the same child
+func subSagaID(parentSagaName, parentSagaID string, step int) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(fmt.Sprintf("%s/%s/%d", parentSagaName, parentSagaID, step))).String()
+}
+
+// subSagaParents are the reply channels of the sagas that execute a saga in a SubSagaStep, by saga name
+type subSagaParents struct {
+	mu            sync.Mutex
+	replyChannels map[string]string
+}
+
+func (o *Orchestrator) addParent(sagaName, replyChannel string) {
+	o.parents.mu.Lock()
+	defer o.parents.mu.Unlock()
+
+	o.parents.replyChannels[sagaName] = replyChannel
+}
+
+// executeSubSaga starts, or compensates, the child saga of the current step
+func (o *Orchestrator) executeSubSaga(ctx context.Context, instance *Instance, results *stepResults) error {
+	child := results.subSaga.step.orchestrator
+	childID := subSagaID(instance.sagaName, instance.sagaID, results.updatedStepContext.step)
+
+	// the child echoes the step of the parent when it replies, so a reply the parent has moved on from is dropped
+	parentStep := &ParentStep{
+		Step:         instance.currentStep,
+		Compensating: instance.compensating,
+		Sequence:     instance.executionState.Sequence,
+	}
+
+	existing, err := child.instanceStore.Find(ctx, child.SagaName(), childID)
+	if err != nil && !errors.Is(err, ErrInstanceNotFound) {
+		return err
+	}
+
+	if results.subSaga.compensating {
+		if existing == nil || (existing.endState && existing.compensating) {
+			// the child was never started or has compensated itself already
+			results.local = true
+			return nil
+		}
+
+		return child.compensateSubSaga(ctx, childID, parentStep)
+	}
+
+	if existing != nil {
+		if existing.endState {
+			// the step is executed again; the outcome is sent again
+			existing.executionState.ParentStep = parentStep
+			return child.notifyParent(ctx, existing)
+		}
+		return nil
+	}
+
+	_, err = child.start(ctx, &Instance{
+		sagaID:         childID,
+		sagaName:       child.SagaName(),
+		sagaData:       results.subSaga.sagaData,
+		parentSagaName: instance.sagaName,
+		parentSagaID:   instance.sagaID,
+		executionState: ExecutionState{ParentStep: parentStep},
+	})
+
+	return err
+}
+
+// compensateSubSaga compensates a child saga for its parent, whether it is running or has completed
+func (o *Orchestrator) compensateSubSaga(ctx context.Context, sagaID string, parentStep *ParentStep) error {
+	return o.retryConflicts(ctx, func(ctx context.Context) error {
+		instance, err := o.instanceStore.Find(ctx, o.definition.SagaName(), sagaID)
+		if err != nil {
+			return err
+		}
+
+		v, err := o.forInstance(instance)
+		if err != nil {
+			return err
+		}
+
+		instance.executionState.Reverted = true
+		instance.executionState.ParentStep = parentStep
+		stepCtx := instance.getStepContext()
+
+		var results *stepResults
+		switch {
+		case stepCtx.ended:
+			// starting after the last step compensates every step
+			stepCtx.ended = false
+			stepCtx.step = len(v.definition.Steps())
+			stepCtx.compensating = isCompensating
+			results = v.executeNextStep(ctx, stepCtx, instance.SagaData())
+		case stepCtx.compensating:
+			// the child is compensating already; it replies once it is done
+			results = &stepResults{updatedStepContext: stepCtx, waiting: true}
+		default:
+			results = v.compensateCurrentStep(ctx, stepCtx, instance.SagaData())
+		}
+
+		return v.processResults(ctx, instance, results, historyCause{event: HistoryForcedCompensation})
+	})
+}
+
+// notifyParent replies to the parent of a child saga with the outcome of the child
+func (o *Orchestrator) notifyParent(ctx context.Context, instance *Instance) error {
+	o.parents.mu.Lock()
+	replyChannel, ok := o.parents.replyChannels[instance.parentSagaName]
+	o.parents.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("parent saga `%s` has no SubSagaStep for `%s`", instance.parentSagaName, o.definition.SagaName())
+	}
+
+	publisher, ok := o.publisher.(msg.ReplyMessagePublisher)
+	if !ok {
+		return fmt.Errorf("the publisher of saga `%s` cannot publish replies", o.definition.SagaName())
+	}
+
+	builder := msg.WithReply(SubSagaEnded{
+		SagaName:    instance.sagaName,
+		SagaID:      instance.sagaID,
+		Compensated: instance.compensating,
+	})
+
+	// a child compensated for its parent has done what the parent asked
+	reply := builder.Success()
+	if instance.compensating && !instance.executionState.Reverted {
+		reply = builder.Failure()
+	}
+
+	o.logger.Info("replying to parent saga",
+		zap.String("SagaName", instance.sagaName),
+		zap.String("SagaID", instance.sagaID),
+		zap.String("ParentSagaName", instance.parentSagaName),
+		zap.String("ParentSagaID", instance.parentSagaID),
+	)
+
+	headers := msg.Headers{
+		MessageReplySagaName: instance.parentSagaName,
+		MessageReplySagaID:   instance.parentSagaID,
+	}
+	// children started before the parent step was saved reply without it, and their replies are never stale
+	if parentStep := instance.executionState.ParentStep; parentStep != nil {
+		headers[MessageReplySagaStep] = strconv.Itoa(parentStep.Step)
+		headers[MessageReplySagaCompensating] = strconv.FormatBool(parentStep.Compensating)
+		headers[MessageReplySagaSequence] = strconv.Itoa(parentStep.Sequence)
+	}
+
+	return publisher.PublishReply(ctx, reply.Reply(),
+		msg.WithHeaders(reply.Headers()),
+		msg.WithHeaders(headers),
+		msg.WithDestinationChannel(replyChannel),
+	)
+}
@@ -0,0 +1,155 @@
+package saga
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/core"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+)
+
+// Timeout scheduler defaults
+const (
+	DefaultTimeoutSchedulerInterval  = 10 * time.Second
+	DefaultTimeoutSchedulerBatchSize = 100
+)
+
+// TimeoutScheduler finds overdue saga instances and delivers a timeout reply to their Orchestrator
+type TimeoutScheduler struct {
+	orchestrators []*Orchestrator
+	middlewares   []func(msg.MessageReceiver) msg.MessageReceiver
+	locker        Locker
+	interval      time.Duration
+	batchSize     int
+	logger        logger.Logger
+}
+
+// NewTimeoutScheduler constructs a new TimeoutScheduler
+func NewTimeoutScheduler(logger logger.Logger, options ...TimeoutSchedulerOption) *TimeoutScheduler {
+	s := &TimeoutScheduler{
+		interval:  DefaultTimeoutSchedulerInterval,
+		batchSize: DefaultTimeoutSchedulerBatchSize,
+		logger:    logger,
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	s.logger.Info("saga.TimeoutScheduler constructed")
+
+	return s
+}
+
+// Register adds orchestrators whose instances will be checked for timeouts
+func (s *TimeoutScheduler) Register(orchestrators ...*Orchestrator) *TimeoutScheduler {
+	for _, orchestrator := range orchestrators {
+		if _, ok := orchestrator.instanceStore.(OverdueFinder); !ok {
+			s.logger.Error("not checking saga for timeouts; the instance store is not a saga.OverdueFinder", zap.String("SagaName", orchestrator.definition.SagaName()))
+			continue
+		}
+		s.orchestrators = append(s.orchestrators, orchestrator)
+	}
+
+	return s
+}
+
+// Start checks for overdue instances until the context is cancelled
+func (s *TimeoutScheduler) Start(ctx context.Context) error {
+	receivers := make([]msg.MessageReceiver, len(s.orchestrators))
+	for i, orchestrator := range s.orchestrators {
+		receivers[i] = s.chain(orchestrator)
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		for i, orchestrator := range s.orchestrators {
+			s.expire(ctx, orchestrator, receivers[i])
+		}
+	}
+}
+
+func (s *TimeoutScheduler) expire(ctx context.Context, orchestrator *Orchestrator, receiver msg.MessageReceiver) {
+	logger := s.logger.With(zap.String("SagaName", orchestrator.definition.SagaName()))
+
+	if s.locker != nil {
+		unlock, locked, err := s.locker.TryLock(ctx)
+		if err != nil {
+			logger.Error("error locking saga timeouts", zap.Error(err))
+			return
+		}
+		if !locked {
+			logger.Debug("saga timeouts locked by another process")
+			return
+		}
+		defer func() {
+			if err := unlock(ctx); err != nil {
+				logger.Error("error unlocking saga timeouts", zap.Error(err))
+			}
+		}()
+	}
+
+	var instances []*Instance
+
+	// find the batch through the middleware as well, so it is found in a session for the stores that require one
+	find := s.chain(msg.NamedReceiver(orchestrator.ReceiverName()+".timeouts", msg.ReceiveMessageFunc(
+		func(ctx context.Context, _ msg.Message) (err error) {
+			instances, err = orchestrator.instanceStore.(OverdueFinder).FindOverdue(ctx, orchestrator.definition.SagaName(), time.Now(), s.batchSize)
+			return err
+		},
+	)))
+
+	message := msg.NewMessage(nil)
+	if err := find.ReceiveMessage(core.SetRequestContext(ctx, message.ID(), "", ""), message); err != nil {
+		logger.Error("error finding overdue saga instances", zap.Error(err))
+		return
+	}
+
+	for _, instance := range instances {
+		message, err := timeoutMessage(instance)
+		if err != nil {
+			logger.Error("error creating saga timeout message", zap.Error(err))
+			return
+		}
+
+		mCtx := core.SetRequestContext(ctx, message.ID(), "", "")
+
+		if err = receiver.ReceiveMessage(mCtx, message); err != nil {
+			logger.Error("error handling saga timeout", zap.String("SagaID", instance.sagaID), zap.Error(err))
+		}
+	}
+}
+
+func (s *TimeoutScheduler) chain(receiver msg.MessageReceiver) msg.MessageReceiver {
+	for i := len(s.middlewares) - 1; i >= 0; i-- {
+		receiver = s.middlewares[i](receiver)
+	}
+
+	return receiver
+}
+
+func timeoutMessage(instance *Instance) (msg.Message, error) {
+	reply := msg.WithReply(msg.Failure{}).Headers(map[string]string{
+		MessageReplySagaID:      instance.sagaID,
+		MessageReplySagaName:    instance.sagaName,
+		MessageReplySagaTimeout: time.Now().Format(time.RFC3339),
+	}).Failure()
+
+	payload, err := core.SerializeReply(reply.Reply())
+	if err != nil {
+		return nil, err
+	}
+
+	return msg.NewMessage(payload, msg.WithHeaders(reply.Headers())), nil
+}
@@ -0,0 +1,46 @@
+package saga
+
+import (
+	"time"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+)
+
+// TimeoutSchedulerOption options for TimeoutScheduler
+type TimeoutSchedulerOption func(s *TimeoutScheduler)
+
+// WithTimeoutSchedulerInterval is an option to set how often the TimeoutScheduler checks for overdue instances
+func WithTimeoutSchedulerInterval(interval time.Duration) TimeoutSchedulerOption {
+	return func(s *TimeoutScheduler) {
+		s.interval = interval
+	}
+}
+
+// WithTimeoutSchedulerBatchSize is an option to set how many overdue instances are handled per saga in each check
+func WithTimeoutSchedulerBatchSize(batchSize int) TimeoutSchedulerOption {
+	return func(s *TimeoutScheduler) {
+		s.batchSize = batchSize
+	}
+}
+
+// WithTimeoutSchedulerMiddleware is an option to add the receiver middleware timeouts are delivered through
+func WithTimeoutSchedulerMiddleware(mws ...func(msg.MessageReceiver) msg.MessageReceiver) TimeoutSchedulerOption {
+	return func(s *TimeoutScheduler) {
+		s.middlewares = append(s.middlewares, mws...)
+	}
+}
+
+// WithTimeoutSchedulerLocker is an option to only expire instances while holding the lock, e.g. a pgx.AdvisoryLocker
+func WithTimeoutSchedulerLocker(locker Locker) TimeoutSchedulerOption {
+	return func(s *TimeoutScheduler) {
+		s.locker = locker
+	}
+}
+
+// WithTimeoutSchedulerLogger is an option to set the logger.Logger of the TimeoutScheduler
+func WithTimeoutSchedulerLogger(logger logger.Logger) TimeoutSchedulerOption {
+	return func(s *TimeoutScheduler) {
+		s.logger = logger
+	}
+}
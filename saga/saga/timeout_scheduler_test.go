@@ -0,0 +1,122 @@
+package saga_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/core"
+	"github.com/nguyenta1993/service-kit/saga/memory"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+	"github.com/nguyenta1993/service-kit/saga/saga"
+)
+
+type sessionKey struct{}
+
+// sessionStore requires a session for FindOverdue, as the session client stores require a transaction
+type sessionStore struct {
+	*memory.SagaInstanceStore
+}
+
+func (s sessionStore) FindOverdue(ctx context.Context, sagaName string, now time.Time, limit int) ([]*saga.Instance, error) {
+	if ctx.Value(sessionKey{}) == nil {
+		return nil, errors.New("no session in context")
+	}
+
+	return s.SagaInstanceStore.FindOverdue(ctx, sagaName, now, limit)
+}
+
+// mutexLocker is a saga.Locker shared by the schedulers of the test as if they ran in other processes
+type mutexLocker struct {
+	mu     sync.Mutex
+	locked bool
+}
+
+func (l *mutexLocker) TryLock(context.Context) (func(context.Context) error, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.locked {
+		return nil, false, nil
+	}
+	l.locked = true
+
+	return func(context.Context) error {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		l.locked = false
+		return nil
+	}, true, nil
+}
+
+func TestTimeoutScheduler_Start(t *testing.T) {
+	log := logger.NewDefaultLogger("error")
+
+	definition, err := saga.NewDefinition("saga_test.timeouts", "timeout-replies").
+		Step(saga.NewRemoteStep().
+			Action(func(context.Context, core.SagaData) msg.DomainCommand {
+				return orderCommand{Channel: "inventory"}
+			}, saga.WithRemoteStepTimeout(time.Millisecond)).
+			NonCompensatable()).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	sessionMiddleware := func(next msg.MessageReceiver) msg.MessageReceiver {
+		return msg.ReceiveMessageFunc(func(ctx context.Context, message msg.Message) error {
+			return next.ReceiveMessage(context.WithValue(ctx, sessionKey{}, 0), message)
+		})
+	}
+
+	tests := map[string]struct {
+		heldElsewhere bool
+		wantEnded     bool
+	}{
+		"Expired":       {wantEnded: true},
+		"LockedByOther": {heldElsewhere: true, wantEnded: false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			store := sessionStore{SagaInstanceStore: memory.NewSagaInstanceStore()}
+			broker := memory.NewBroker(log)
+			orchestrator := saga.NewOrchestrator(definition, store, msg.NewPublisher(broker.Producer(), log), log)
+
+			started, err := orchestrator.Start(ctx, &orderData{OrderID: "order-id"})
+			if err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+
+			locker := &mutexLocker{locked: tt.heldElsewhere}
+			scheduler := saga.NewTimeoutScheduler(log,
+				saga.WithTimeoutSchedulerInterval(5*time.Millisecond),
+				saga.WithTimeoutSchedulerMiddleware(sessionMiddleware),
+				saga.WithTimeoutSchedulerLocker(locker),
+			).Register(orchestrator)
+
+			sCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+			defer cancel()
+
+			if err = scheduler.Start(sCtx); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+
+			instance, err := store.Find(ctx, started.SagaName(), started.SagaID())
+			if err != nil {
+				t.Fatalf("Find() error = %v", err)
+			}
+			if instance.EndState() != tt.wantEnded {
+				t.Errorf("instance EndState = %v, want %v", instance.EndState(), tt.wantEnded)
+			}
+			if locker.locked != tt.heldElsewhere {
+				t.Errorf("locker locked = %v, want %v", locker.locked, tt.heldElsewhere)
+			}
+		})
+	}
+}
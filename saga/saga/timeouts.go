@@ -0,0 +1,55 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nguyenta1993/service-kit/saga/msg"
+)
+
+// handleTimeout fails an overdue step or sends an overdue compensation again
+func (o *Orchestrator) handleTimeout(ctx context.Context, instance *Instance, message msg.Reply) (*stepResults, error) {
+	logger := o.logger.With(
+		zap.String("SagaName", o.definition.SagaName()),
+		zap.String("SagaID", instance.sagaID),
+		zap.Int("Step", instance.currentStep),
+	)
+
+	// a reply may have been processed after the instance was found to be overdue
+	if !instance.Overdue(time.Now()) {
+		logger.Info("saga instance is no longer overdue")
+		return nil, nil
+	}
+
+	stepCtx := instance.getStepContext()
+
+	if stepCtx.step >= len(o.definition.Steps()) || stepCtx.step < 0 {
+		logger.Error("current step is out of bounds")
+		return nil, fmt.Errorf("current step is out of bounds: 0-%d, got %d", len(o.definition.Steps()), stepCtx.step)
+	}
+
+	// the step deadline is the retry delay while retrying; an overdue saga compensates instead
+	stepOverdue := !instance.stepDeadline.IsZero() && instance.stepDeadline.Before(time.Now())
+
+	if stepCtx.retrying && stepOverdue && !stepCtx.cancelled {
+		logger.Info("sending the failed step again", zap.Int("Attempts", stepCtx.attempts))
+		return o.executeCurrentStep(ctx, stepCtx.retried(), instance.SagaData()), nil
+	}
+
+	if stepCtx.compensating {
+		logger.Warn("saga compensation timed out; sending it again")
+		return o.executeCurrentStep(ctx, stepCtx, instance.SagaData()), nil
+	}
+
+	if stepOverdue {
+		if results := o.retryStep(ctx, stepCtx, instance.SagaData(), message); results != nil {
+			return results, nil
+		}
+	}
+
+	logger.Warn("saga timed out; treating it as a failure")
+	return o.handleReply(ctx, stepCtx, instance.SagaData(), message)
+}
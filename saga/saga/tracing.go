@@ -0,0 +1,97 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/nguyenta1993/service-kit/saga/msg"
+)
+
+var tracer = otel.Tracer("github.com/nguyenta1993/service-kit/saga")
+
+// headersCarrier is a propagation.TextMapCarrier for the trace headers of saga messages
+type headersCarrier struct {
+	headers msg.Headers
+	prefix  string
+}
+
+var _ propagation.TextMapCarrier = (*headersCarrier)(nil)
+
+func (c headersCarrier) Get(key string) string {
+	return c.headers.Get(c.prefix + key)
+}
+
+func (c headersCarrier) Set(key, value string) {
+	c.headers[c.prefix+key] = value
+}
+
+func (c headersCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.headers))
+	for key := range c.headers {
+		if strings.HasPrefix(key, c.prefix) {
+			keys = append(keys, key[len(c.prefix):])
+		}
+	}
+
+	return keys
+}
+
+// startSagaSpan starts the root span of a new instance and saves its trace context with the instance
+func (o *Orchestrator) startSagaSpan(ctx context.Context, instance *Instance) (context.Context, trace.Span) {
+	ctx, span := tracer.Start(ctx, "saga "+instance.sagaName, trace.WithAttributes(
+		attribute.String("saga.name", instance.sagaName),
+		attribute.String("saga.id", instance.sagaID),
+	))
+
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	instance.executionState.TraceContext = carrier
+
+	return ctx, span
+}
+
+// sagaContext returns the context with the root span of the instance as the current span
+func (o *Orchestrator) sagaContext(ctx context.Context, instance *Instance) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(instance.executionState.TraceContext))
+}
+
+// commandTraceHeaders are the headers that carry the trace of the instance to the participants
+func (o *Orchestrator) commandTraceHeaders(ctx context.Context, instance *Instance) msg.Headers {
+	headers := msg.Headers{}
+	otel.GetTextMapPropagator().Inject(o.sagaContext(ctx, instance), headersCarrier{headers, MessageCommandTracePrefix})
+
+	return headers
+}
+
+// recordStepSpan records the span of a step that has ended, linked to the message being processed and the reply
+func (o *Orchestrator) recordStepSpan(ctx context.Context, instance *Instance, stepCtx stepContext, startedAt time.Time, failed bool, cause historyCause) {
+	links := []trace.Link{trace.LinkFromContext(ctx)}
+	if cause.reply != nil {
+		replyCtx := otel.GetTextMapPropagator().Extract(context.Background(), headersCarrier{cause.reply.Headers(), MessageReplyTracePrefix})
+		links = append(links, trace.LinkFromContext(replyCtx))
+	}
+
+	_, span := tracer.Start(o.sagaContext(ctx, instance), fmt.Sprintf("saga %s step %d", instance.sagaName, stepCtx.step),
+		trace.WithTimestamp(startedAt),
+		trace.WithLinks(links...),
+		trace.WithAttributes(
+			attribute.String("saga.name", instance.sagaName),
+			attribute.String("saga.id", instance.sagaID),
+			attribute.Int("saga.step", stepCtx.step),
+			attribute.Bool("saga.compensating", stepCtx.compensating),
+			attribute.String("saga.event", string(cause.event)),
+		),
+	)
+	if failed {
+		span.SetStatus(codes.Error, "saga step failed")
+	}
+	span.End()
+}
@@ -0,0 +1,68 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// DefinitionVersionStatus is the state of a definition version hosted by an Orchestrator
+type DefinitionVersionStatus struct {
+	Version int `json:"version"`
+	// Current is set for the version new instances are started with
+	Current bool `json:"current"`
+	// Drained is set once no running or compensating instance was started by the version
+	Drained bool `json:"drained"`
+}
+
+// Versions reports the hosted definition versions in order and whether each has drained
+func (o *Orchestrator) Versions(ctx context.Context) ([]DefinitionVersionStatus, error) {
+	lister, ok := o.instanceStore.(InstanceLister)
+	if !ok {
+		return nil, fmt.Errorf("listing instances: %w", ErrStoreNotSupported)
+	}
+
+	versions := make([]int, 0, len(o.versions))
+	for version := range o.versions {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+
+	statuses := make([]DefinitionVersionStatus, 0, len(versions))
+	for _, version := range versions {
+		status := DefinitionVersionStatus{
+			Version: version,
+			Current: version == DefinitionVersion(o.definition),
+			Drained: true,
+		}
+
+		for _, state := range []InstanceState{InstanceRunning, InstanceCompensating} {
+			instances, err := lister.List(ctx, InstanceQuery{
+				SagaName:          o.definition.SagaName(),
+				State:             state,
+				DefinitionVersion: version,
+				Limit:             1,
+			})
+			if err != nil {
+				return nil, err
+			}
+			if len(instances) > 0 {
+				status.Drained = false
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// forInstance returns the orchestrator of the definition version that started the instance
+func (o *Orchestrator) forInstance(instance *Instance) (*Orchestrator, error) {
+	v, exists := o.versions[instance.DefinitionVersion()]
+	if !exists {
+		return nil, fmt.Errorf("saga instance %s/%s: %w: %d", instance.sagaName, instance.sagaID, ErrDefinitionVersionNotHosted, instance.DefinitionVersion())
+	}
+
+	return v, nil
+}
@@ -0,0 +1,96 @@
+package saga_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/core"
+	"github.com/nguyenta1993/service-kit/saga/memory"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+	"github.com/nguyenta1993/service-kit/saga/saga"
+)
+
+func TestOrchestrator_DefinitionVersions(t *testing.T) {
+	ctx := context.Background()
+	log := logger.NewDefaultLogger("error")
+
+	v1, err := saga.NewDefinition("saga_test.versioned", "versioned-replies").
+		Step(remoteStep("inventory"), remoteStep("payment")).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	// version 2 inserts a step before the payment
+	v2, err := saga.NewDefinition("saga_test.versioned", "versioned-replies").
+		Version(2).
+		Step(remoteStep("inventory"), remoteStep("fraud"), remoteStep("payment")).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	store := memory.NewSagaInstanceStore()
+	broker := memory.NewBroker(log)
+	publisher := msg.NewPublisher(broker.Producer(), log)
+
+	started, err := saga.NewOrchestrator(v1, store, publisher, log).Start(ctx, &orderData{OrderID: "order-id"})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	orchestrator := saga.NewOrchestrator(v2, store, publisher, log, saga.WithOrchestratorDefinitions(v1))
+
+	statuses, err := orchestrator.Versions(ctx)
+	if err != nil {
+		t.Fatalf("Versions() error = %v", err)
+	}
+	want := []saga.DefinitionVersionStatus{{Version: 1}, {Version: 2, Current: true, Drained: true}}
+	if len(statuses) != 2 || statuses[0] != want[0] || statuses[1] != want[1] {
+		t.Errorf("Versions() = %+v, want %+v", statuses, want)
+	}
+
+	if err = orchestrator.ReceiveMessage(ctx, successReply(t, started.SagaName(), started.SagaID())); err != nil {
+		t.Fatalf("ReceiveMessage() error = %v", err)
+	}
+
+	// the instance continues with the steps of the version that started it
+	if published := broker.Published("fraud"); len(published) != 0 {
+		t.Errorf("published %d commands to the step added by version 2", len(published))
+	}
+	if published := broker.Published("payment"); len(published) != 1 {
+		t.Errorf("published %d commands to the payment step of version 1, want 1", len(published))
+	}
+
+	instance, err := store.Find(ctx, started.SagaName(), started.SagaID())
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if instance.DefinitionVersion() != 1 || instance.CurrentStep() != 1 {
+		t.Errorf("instance version = %d, step = %d; want version 1, step 1", instance.DefinitionVersion(), instance.CurrentStep())
+	}
+}
+
+// baseStore only implements saga.InstanceStore, as third-party stores may
+type baseStore struct {
+	saga.InstanceStore
+}
+
+func TestOrchestrator_VersionsWithoutLister(t *testing.T) {
+	log := logger.NewDefaultLogger("error")
+
+	definition, err := saga.NewDefinition("saga_test.unlisted", "unlisted-replies").
+		Step(saga.NewLocalStep(func(context.Context, core.SagaData) error { return nil })).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	orchestrator := saga.NewOrchestrator(definition, baseStore{memory.NewSagaInstanceStore()}, msg.NewPublisher(memory.NewBroker(log).Producer(), log), log)
+
+	if _, err = orchestrator.Versions(context.Background()); !errors.Is(err, saga.ErrStoreNotSupported) {
+		t.Errorf("Versions() error = %v, want %v", err, saga.ErrStoreNotSupported)
+	}
+}
@@ -0,0 +1,54 @@
+package saga
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nguyenta1993/service-kit/saga/core"
+)
+
+// StartAndWait creates a new instance of the saga and waits until it has ended or the timeout has passed
+func (o *Orchestrator) StartAndWait(ctx context.Context, sagaData core.SagaData, timeout time.Duration) (*Instance, error) {
+	instance := &Instance{
+		sagaID:   uuid.New().String(),
+		sagaName: o.definition.SagaName(),
+		sagaData: sagaData,
+	}
+
+	// listen before starting; the instance may end before Start returns
+	ended, stop := o.notifier.Listen(instance.sagaName, instance.sagaID)
+	defer stop()
+
+	instance, err := o.startInSession(ctx, instance)
+	if err != nil || instance == nil || instance.endState {
+		return instance, err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	ticker := time.NewTicker(o.waitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+			return o.instanceStore.Find(ctx, instance.sagaName, instance.sagaID)
+		case <-ended:
+			// a closed channel is ready forever; the ticker keeps finding the instance if it is not saved yet
+			ended = nil
+		case <-ticker.C:
+		}
+
+		found, err := o.instanceStore.Find(ctx, instance.sagaName, instance.sagaID)
+		if err != nil {
+			return nil, err
+		}
+		if found.endState {
+			return found, nil
+		}
+	}
+}
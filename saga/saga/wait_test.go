@@ -0,0 +1,93 @@
+package saga_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/core"
+	"github.com/nguyenta1993/service-kit/saga/memory"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+	"github.com/nguyenta1993/service-kit/saga/saga"
+)
+
+func TestOrchestrator_StartAndWait(t *testing.T) {
+	log := logger.NewDefaultLogger("error")
+
+	definition, err := saga.NewDefinition("saga_test.waited", "waited-replies").
+		Step(remoteStep("inventory")).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	tests := map[string]struct {
+		reply   bool
+		timeout time.Duration
+		wantEnd bool
+	}{
+		"Completed": {reply: true, timeout: 10 * time.Second, wantEnd: true},
+		"TimedOut":  {reply: false, timeout: 50 * time.Millisecond, wantEnd: false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+			defer cancel()
+
+			store := memory.NewSagaInstanceStore()
+			broker := memory.NewBroker(log)
+			// polling is slower than the test; only the notifier can end the wait
+			orchestrator := saga.NewOrchestrator(definition, store, msg.NewPublisher(broker.Producer(), log), log,
+				saga.WithOrchestratorWaitPollInterval(time.Minute),
+			)
+
+			if tt.reply {
+				go func() {
+					command, err := broker.WaitFor(ctx, "inventory", nil)
+					if err != nil {
+						return
+					}
+					reply := successReply(t, command.Headers().Get(saga.MessageCommandSagaName), command.Headers().Get(saga.MessageCommandSagaID))
+					if err = orchestrator.ReceiveMessage(ctx, reply); err != nil {
+						t.Errorf("ReceiveMessage() error = %v", err)
+					}
+				}()
+			}
+
+			instance, err := orchestrator.StartAndWait(ctx, &orderData{OrderID: "order-id"}, tt.timeout)
+			if err != nil {
+				t.Fatalf("StartAndWait() error = %v", err)
+			}
+			if instance.EndState() != tt.wantEnd {
+				t.Errorf("StartAndWait() EndState = %v, want %v", instance.EndState(), tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestOrchestrator_StartAndWaitFailure(t *testing.T) {
+	log := logger.NewDefaultLogger("error")
+
+	errReserve := errors.New("reserve failed")
+
+	definition, err := saga.NewDefinition("saga_test.failed_start", "failed-start-replies").
+		Step(saga.NewLocalStep(func(context.Context, core.SagaData) error {
+			return errReserve
+		})).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	orchestrator := saga.NewOrchestrator(definition, memory.NewSagaInstanceStore(), msg.NewPublisher(memory.NewBroker(log).Producer(), log), log)
+
+	instance, err := orchestrator.StartAndWait(context.Background(), &orderData{OrderID: "order-id"}, time.Second)
+	if !errors.Is(err, errReserve) {
+		t.Errorf("StartAndWait() error = %v, want %v", err, errReserve)
+	}
+	if instance != nil {
+		t.Errorf("StartAndWait() instance = %v, want nil", instance)
+	}
+}
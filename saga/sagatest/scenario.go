@@ -0,0 +1,371 @@
+package sagatest
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/core"
+	"github.com/nguyenta1993/service-kit/saga/memory"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+	_ "github.com/nguyenta1993/service-kit/saga/msgpack"
+	"github.com/nguyenta1993/service-kit/saga/saga"
+)
+
+// Scenario executes a saga definition with in-memory infrastructure and asserts how it behaves
+type Scenario struct {
+	t            testing.TB
+	ctx          context.Context
+	definition   *recordingDefinition
+	orchestrator *saga.Orchestrator
+	events       *msg.EventDispatcher
+	broker       *memory.Broker
+	store        *memory.SagaInstanceStore
+	history      *memory.SagaHistoryStore
+	sagaID       string
+	received     map[string]int
+	lastCommand  msg.Message
+}
+
+type recordingDefinition struct {
+	saga.Definition
+	mu    sync.Mutex
+	hooks []saga.LifecycleHook
+}
+
+// NewScenario constructs a new Scenario for the definition; options are passed on to the saga.Orchestrator
+func NewScenario(t testing.TB, definition saga.Definition, options ...saga.OrchestratorOption) *Scenario {
+	t.Helper()
+
+	log := logger.NewDefaultLogger("error")
+
+	s := &Scenario{
+		t:          t,
+		ctx:        context.Background(),
+		definition: &recordingDefinition{Definition: definition},
+		broker:     memory.NewBroker(log),
+		store:      memory.NewSagaInstanceStore(),
+		history:    memory.NewSagaHistoryStore(),
+		received:   map[string]int{},
+	}
+
+	options = append([]saga.OrchestratorOption{saga.WithOrchestratorHistoryStore(s.history)}, options...)
+
+	s.orchestrator = saga.NewOrchestrator(s.definition, s.store, msg.NewPublisher(s.broker.Producer(), log), log, options...)
+
+	s.events = msg.NewEventDispatcher(log)
+	s.orchestrator.HandleEvents(s.events)
+
+	return s
+}
+
+// Start starts the saga with the saga data
+func (s *Scenario) Start(sagaData core.SagaData) *Scenario {
+	s.t.Helper()
+
+	instance, err := s.orchestrator.Start(s.ctx, sagaData)
+	if err != nil {
+		s.t.Fatalf("saga failed to start: %v", err)
+	}
+	if instance == nil {
+		s.t.Fatalf("saga failed to start")
+	}
+
+	s.sagaID = instance.SagaID()
+
+	return s
+}
+
+// ExpectCommand asserts the next command published to the channel has the type of command
+func (s *Scenario) ExpectCommand(command core.Command, channel string, asserts ...func(t testing.TB, command core.Command)) *Scenario {
+	s.t.Helper()
+
+	published := s.broker.Published(channel)
+	if s.received[channel] >= len(published) {
+		s.t.Fatalf("expected command `%s` on channel `%s`; no command was published", command.CommandName(), channel)
+	}
+
+	message := published[s.received[channel]]
+	s.received[channel]++
+
+	commandName := message.Headers().Get(msg.MessageCommandName)
+	if commandName != command.CommandName() {
+		s.t.Fatalf("expected command `%s` on channel `%s`; got `%s`", command.CommandName(), channel, commandName)
+	}
+
+	decoded, err := core.DeserializeCommand(core.VersionedName(commandName, msg.SchemaVersion(message.Headers())), message.Payload())
+	if err != nil {
+		s.t.Fatalf("error decoding command `%s`: %v", commandName, err)
+	}
+
+	for _, assert := range asserts {
+		assert(s.t, decoded)
+	}
+
+	s.lastCommand = message
+
+	return s
+}
+
+// ExpectNoCommands asserts that no more commands were published to the channels
+func (s *Scenario) ExpectNoCommands(channels ...string) *Scenario {
+	s.t.Helper()
+
+	for _, channel := range channels {
+		if published := s.broker.Published(channel); s.received[channel] < len(published) {
+			s.t.Errorf("expected no more commands on channel `%s`; got `%s`", channel, published[s.received[channel]].Headers().Get(msg.MessageCommandName))
+		}
+	}
+
+	return s
+}
+
+// ReplySuccess replies to the last expected command with a success outcome and the optional reply
+func (s *Scenario) ReplySuccess(reply ...core.Reply) *Scenario {
+	s.t.Helper()
+
+	return s.Reply(msg.WithReply(firstReply(reply)).Success())
+}
+
+// ReplyFailure replies to the last expected command with a failure outcome and the optional reply
+func (s *Scenario) ReplyFailure(reply ...core.Reply) *Scenario {
+	s.t.Helper()
+
+	return s.Reply(msg.WithReply(firstReply(reply)).Failure())
+}
+
+// Reply replies to the last expected command; use msg.WithReply to build the reply
+func (s *Scenario) Reply(reply msg.Reply) *Scenario {
+	s.t.Helper()
+
+	if s.lastCommand == nil {
+		s.t.Fatalf("cannot reply; no command has been expected")
+	}
+
+	payload, err := core.SerializeReply(reply.Reply())
+	if err != nil {
+		s.t.Fatalf("error encoding reply `%s`: %v", reply.Reply().ReplyName(), err)
+	}
+
+	message := msg.NewMessage(payload,
+		msg.WithHeaders(correlationHeaders(s.lastCommand.Headers())),
+		msg.WithHeaders(msg.Headers{msg.MessageSchemaVersion: strconv.Itoa(core.TypeVersion(reply.Reply().ReplyName()))}),
+		msg.WithHeaders(reply.Headers()),
+	)
+
+	if err = s.orchestrator.ReceiveMessage(s.ctx, message); err != nil {
+		s.t.Fatalf("error receiving reply `%s`: %v", reply.Reply().ReplyName(), err)
+	}
+
+	return s
+}
+
+// Event sends the event to the instances waiting in an AwaitStep
+func (s *Scenario) Event(event core.Event, headers ...msg.Headers) *Scenario {
+	s.t.Helper()
+
+	payload, err := core.SerializeEvent(event)
+	if err != nil {
+		s.t.Fatalf("error encoding event `%s`: %v", event.EventName(), err)
+	}
+
+	options := []msg.MessageOption{msg.WithHeaders(msg.Headers{
+		msg.MessageEventName:     event.EventName(),
+		msg.MessageSchemaVersion: strconv.Itoa(core.TypeVersion(event.EventName())),
+	})}
+	for _, h := range headers {
+		options = append(options, msg.WithHeaders(h))
+	}
+
+	if err = s.events.ReceiveMessage(s.ctx, msg.NewMessage(payload, options...)); err != nil {
+		s.t.Fatalf("error receiving event `%s`: %v", event.EventName(), err)
+	}
+
+	return s
+}
+
+// ExpectSubSaga asserts the saga started a child saga in the scenario of the child orchestrator
+func (s *Scenario) ExpectSubSaga(child *Scenario) *Scenario {
+	s.t.Helper()
+
+	instances, err := child.store.FindChildren(s.ctx, s.definition.SagaName(), s.sagaID)
+	if err != nil {
+		s.t.Fatalf("error finding child saga instances: %v", err)
+	}
+	if len(instances) == 0 {
+		s.t.Fatalf("expected a child saga `%s`; none was started", child.definition.SagaName())
+	}
+
+	child.sagaID = instances[len(instances)-1].SagaID()
+
+	return s
+}
+
+// ReplyFromSubSaga receives the next reply the child saga sent when it ended
+func (s *Scenario) ReplyFromSubSaga(child *Scenario) *Scenario {
+	s.t.Helper()
+
+	channel := s.definition.ReplyChannel()
+
+	published := child.broker.Published(channel)
+	if child.received[channel] >= len(published) {
+		s.t.Fatalf("expected a reply from child saga `%s`; none was sent", child.definition.SagaName())
+	}
+
+	message := published[child.received[channel]]
+	child.received[channel]++
+
+	if err := s.orchestrator.ReceiveMessage(s.ctx, message); err != nil {
+		s.t.Fatalf("error receiving reply from child saga `%s`: %v", child.definition.SagaName(), err)
+	}
+
+	return s
+}
+
+// ExpectCompleted asserts the saga ended without compensating
+func (s *Scenario) ExpectCompleted() *Scenario {
+	s.t.Helper()
+
+	if instance := s.Instance(); !instance.EndState() || instance.Compensating() {
+		s.t.Errorf("expected saga to be completed; ended: %t, compensating: %t", instance.EndState(), instance.Compensating())
+	}
+
+	return s
+}
+
+// ExpectCompensated asserts the saga ended after compensating
+func (s *Scenario) ExpectCompensated() *Scenario {
+	s.t.Helper()
+
+	if instance := s.Instance(); !instance.EndState() || !instance.Compensating() {
+		s.t.Errorf("expected saga to be compensated; ended: %t, compensating: %t", instance.EndState(), instance.Compensating())
+	}
+
+	return s
+}
+
+// ExpectRunning asserts the saga has not ended
+func (s *Scenario) ExpectRunning() *Scenario {
+	s.t.Helper()
+
+	if instance := s.Instance(); instance.EndState() {
+		s.t.Errorf("expected saga to be running; it has ended")
+	}
+
+	return s
+}
+
+// ExpectSagaData calls the assert with the saved saga data
+func (s *Scenario) ExpectSagaData(assert func(t testing.TB, sagaData core.SagaData)) *Scenario {
+	s.t.Helper()
+
+	assert(s.t, s.Instance().SagaData())
+
+	return s
+}
+
+// ExpectCompensationPath asserts the steps, by index, that were compensated in order
+func (s *Scenario) ExpectCompensationPath(steps ...int) *Scenario {
+	s.t.Helper()
+
+	records, err := s.history.Timeline(s.ctx, s.definition.SagaName(), s.sagaID)
+	if err != nil {
+		s.t.Fatalf("error reading saga history: %v", err)
+	}
+
+	path := []int{}
+	for _, record := range records {
+		if !record.Compensating || (len(path) > 0 && path[len(path)-1] == record.Step) {
+			continue
+		}
+		path = append(path, record.Step)
+	}
+
+	if !reflect.DeepEqual(path, append([]int{}, steps...)) {
+		s.t.Errorf("expected compensation path %v; got %v", steps, path)
+	}
+
+	return s
+}
+
+// ExpectHooks asserts the lifecycle hooks that were called in order
+func (s *Scenario) ExpectHooks(hooks ...saga.LifecycleHook) *Scenario {
+	s.t.Helper()
+
+	s.definition.mu.Lock()
+	called := append([]saga.LifecycleHook{}, s.definition.hooks...)
+	s.definition.mu.Unlock()
+
+	if !reflect.DeepEqual(called, append([]saga.LifecycleHook{}, hooks...)) {
+		s.t.Errorf("expected hooks %v; got %v", hooks, called)
+	}
+
+	return s
+}
+
+// Instance returns the saved saga instance
+func (s *Scenario) Instance() *saga.Instance {
+	s.t.Helper()
+
+	if s.sagaID == "" {
+		s.t.Fatalf("the saga has not been started")
+	}
+
+	instance, err := s.store.Find(s.ctx, s.definition.SagaName(), s.sagaID)
+	if err != nil {
+		s.t.Fatalf("error finding saga instance: %v", err)
+	}
+
+	return instance
+}
+
+// Broker returns the broker commands are published to
+func (s *Scenario) Broker() *memory.Broker {
+	return s.broker
+}
+
+// Orchestrator returns the orchestrator executing the definition
+func (s *Scenario) Orchestrator() *saga.Orchestrator {
+	return s.orchestrator
+}
+
+// Version keeps the version of the recorded definition
+func (d *recordingDefinition) Version() int {
+	return saga.DefinitionVersion(d.Definition)
+}
+
+func (d *recordingDefinition) OnHook(hook saga.LifecycleHook, instance *saga.Instance) {
+	d.mu.Lock()
+	d.hooks = append(d.hooks, hook)
+	d.mu.Unlock()
+
+	d.Definition.OnHook(hook, instance)
+}
+
+// correlationHeaders turns the command headers into the reply headers a saga.CommandDispatcher sends
+func correlationHeaders(headers msg.Headers) msg.Headers {
+	replyHeaders := msg.Headers{}
+	for key, value := range headers {
+		if key == msg.MessageCommandName || strings.HasPrefix(key, msg.MessageCommandTracePrefix) {
+			continue
+		}
+
+		if strings.HasPrefix(key, msg.MessageCommandPrefix) {
+			replyHeaders[msg.MessageReplyPrefix+key[len(msg.MessageCommandPrefix):]] = value
+		}
+	}
+
+	return replyHeaders
+}
+
+func firstReply(replies []core.Reply) core.Reply {
+	if len(replies) == 0 {
+		return nil
+	}
+
+	return replies[0]
+}
@@ -0,0 +1,394 @@
+package sagatest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nguyenta1993/service-kit/saga/core"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+	"github.com/nguyenta1993/service-kit/saga/retry"
+	"github.com/nguyenta1993/service-kit/saga/saga"
+	"github.com/nguyenta1993/service-kit/saga/sagatest"
+)
+
+type orderData struct {
+	OrderID    string
+	Reserved   bool
+	Authorized bool
+	Released   bool
+}
+
+func (orderData) SagaDataName() string { return "sagatest.orderData" }
+
+type reserveStock struct{ OrderID string }
+
+func (reserveStock) CommandName() string        { return "sagatest.reserveStock" }
+func (reserveStock) DestinationChannel() string { return "inventory" }
+
+type releaseStock struct{ OrderID string }
+
+func (releaseStock) CommandName() string        { return "sagatest.releaseStock" }
+func (releaseStock) DestinationChannel() string { return "inventory" }
+
+type authorizePayment struct{ OrderID string }
+
+func (authorizePayment) CommandName() string        { return "sagatest.authorizePayment" }
+func (authorizePayment) DestinationChannel() string { return "payment" }
+
+type shipOrder struct{ OrderID string }
+
+func (shipOrder) CommandName() string        { return "sagatest.shipOrder" }
+func (shipOrder) DestinationChannel() string { return "shipping" }
+
+type voidPayment struct{ OrderID string }
+
+func (voidPayment) CommandName() string        { return "sagatest.voidPayment" }
+func (voidPayment) DestinationChannel() string { return "payment" }
+
+type paymentData struct{ OrderID string }
+
+func (paymentData) SagaDataName() string { return "sagatest.paymentData" }
+
+type orderApproved struct{ OrderID string }
+
+func (orderApproved) EventName() string { return "sagatest.orderApproved" }
+
+type paymentAuthorized struct{ AuthorizationID string }
+
+func (paymentAuthorized) ReplyName() string { return "sagatest.paymentAuthorized" }
+
+func init() {
+	core.RegisterSagaData(orderData{}, paymentData{})
+	core.RegisterCommands(reserveStock{}, releaseStock{}, authorizePayment{}, voidPayment{}, shipOrder{})
+	core.RegisterReplies(paymentAuthorized{})
+	core.RegisterEvents(orderApproved{})
+}
+
+func orderDefinition(t testing.TB, paymentOptions ...saga.RemoteStepActionOption) saga.Definition {
+	definition, err := saga.NewDefinition("sagatest.order", "order-replies").
+		Step(saga.NewRemoteStep().
+			Action(func(_ context.Context, data core.SagaData) msg.DomainCommand {
+				return reserveStock{OrderID: data.(*orderData).OrderID}
+			}).
+			Compensation(func(_ context.Context, data core.SagaData) msg.DomainCommand {
+				return releaseStock{OrderID: data.(*orderData).OrderID}
+			})).
+		Step(saga.NewLocalStep(func(_ context.Context, data core.SagaData) error {
+			data.(*orderData).Reserved = true
+			return nil
+		}).Compensation(func(_ context.Context, data core.SagaData) error {
+			data.(*orderData).Released = true
+			return nil
+		})).
+		Step(saga.NewRemoteStep().
+			Action(func(_ context.Context, data core.SagaData) msg.DomainCommand {
+				return authorizePayment{OrderID: data.(*orderData).OrderID}
+			}, paymentOptions...).
+			HandleActionReply(paymentAuthorized{}, func(_ context.Context, data core.SagaData, _ core.Reply) error {
+				data.(*orderData).Authorized = true
+				return nil
+			}).
+			NonCompensatable()).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	return definition
+}
+
+func TestScenario(t *testing.T) {
+	tests := map[string]struct {
+		paymentOptions []saga.RemoteStepActionOption
+		run            func(t *testing.T, s *sagatest.Scenario)
+	}{
+		"Completed": {
+			run: func(t *testing.T, s *sagatest.Scenario) {
+				s.Start(&orderData{OrderID: "order-id"}).
+					ExpectCommand(reserveStock{}, "inventory", func(t testing.TB, command core.Command) {
+						if command.(*reserveStock).OrderID != "order-id" {
+							t.Errorf("reserveStock.OrderID = %s, want order-id", command.(*reserveStock).OrderID)
+						}
+					}).
+					ReplySuccess().
+					ExpectCommand(authorizePayment{}, "payment").
+					ReplySuccess(paymentAuthorized{AuthorizationID: "authorization-id"}).
+					ExpectCompleted().
+					ExpectNoCommands("inventory", "payment").
+					ExpectSagaData(func(t testing.TB, data core.SagaData) {
+						if order := data.(*orderData); !order.Reserved || !order.Authorized || order.Released {
+							t.Errorf("saga data = %+v", order)
+						}
+					}).
+					ExpectCompensationPath().
+					ExpectHooks(saga.SagaStarting, saga.SagaCompleted)
+			},
+		},
+		"Compensated": {
+			run: func(t *testing.T, s *sagatest.Scenario) {
+				s.Start(&orderData{OrderID: "order-id"}).
+					ExpectCommand(reserveStock{}, "inventory").
+					ReplySuccess().
+					ExpectCommand(authorizePayment{}, "payment").
+					ReplyFailure().
+					ExpectCommand(releaseStock{}, "inventory").
+					ExpectRunning().
+					ReplySuccess().
+					ExpectCompensated().
+					ExpectSagaData(func(t testing.TB, data core.SagaData) {
+						if order := data.(*orderData); !order.Released || order.Authorized {
+							t.Errorf("saga data = %+v", order)
+						}
+					}).
+					ExpectCompensationPath(1, 0).
+					ExpectHooks(saga.SagaStarting, saga.SagaCompensated)
+			},
+		},
+		"Cancelled": {
+			run: func(t *testing.T, s *sagatest.Scenario) {
+				s.Start(&orderData{OrderID: "order-id"}).
+					ExpectCommand(reserveStock{}, "inventory")
+
+				if err := s.Orchestrator().Cancel(context.Background(), s.Instance().SagaID(), "customer cancelled"); err != nil {
+					t.Fatalf("Cancel() error = %v", err)
+				}
+
+				s.ExpectRunning().
+					ReplySuccess().
+					ExpectCommand(releaseStock{}, "inventory").
+					ExpectNoCommands("payment").
+					ReplySuccess().
+					ExpectCompensated().
+					ExpectCompensationPath(0).
+					ExpectHooks(saga.SagaStarting, saga.SagaCancelled, saga.SagaCompensated)
+			},
+		},
+		"RetriedBeforeCompensating": {
+			paymentOptions: []saga.RemoteStepActionOption{
+				saga.WithRetry(retry.NewConstantBackoff(retry.WithBackoffInitialInterval(0), retry.WithBackoffMaxRetries(2)), nil),
+			},
+			run: func(t *testing.T, s *sagatest.Scenario) {
+				s.Start(&orderData{OrderID: "order-id"}).
+					ExpectCommand(reserveStock{}, "inventory").
+					ReplySuccess().
+					ExpectCommand(authorizePayment{}, "payment").
+					ReplyFailure().
+					ExpectCommand(authorizePayment{}, "payment").
+					ExpectNoCommands("inventory").
+					ReplyFailure().
+					ExpectCommand(releaseStock{}, "inventory").
+					ReplySuccess().
+					ExpectCompensated().
+					ExpectCompensationPath(1, 0)
+			},
+		},
+		"RetriedWithinElapsedTime": {
+			paymentOptions: []saga.RemoteStepActionOption{
+				saga.WithRetry(retry.NewConstantBackoff(
+					retry.WithBackoffInitialInterval(0),
+					retry.WithBackoffMaxRetries(0),
+					retry.WithBackoffMaxElapsed(50*time.Millisecond),
+				), nil),
+			},
+			run: func(t *testing.T, s *sagatest.Scenario) {
+				s.Start(&orderData{OrderID: "order-id"}).
+					ExpectCommand(reserveStock{}, "inventory").
+					ReplySuccess().
+					ExpectCommand(authorizePayment{}, "payment").
+					ReplyFailure().
+					ExpectCommand(authorizePayment{}, "payment").
+					ReplyFailure().
+					ExpectCommand(authorizePayment{}, "payment").
+					ExpectNoCommands("inventory")
+
+				time.Sleep(60 * time.Millisecond)
+
+				s.ReplyFailure().
+					ExpectCommand(releaseStock{}, "inventory").
+					ReplySuccess().
+					ExpectCompensated().
+					ExpectCompensationPath(1, 0)
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tt.run(t, sagatest.NewScenario(t, orderDefinition(t, tt.paymentOptions...)))
+		})
+	}
+}
+
+func TestScenarioPivot(t *testing.T) {
+	definition, err := saga.NewDefinition("sagatest.pivot", "order-replies").
+		Step(saga.NewRemoteStep().
+			Action(func(_ context.Context, data core.SagaData) msg.DomainCommand {
+				return reserveStock{OrderID: data.(*orderData).OrderID}
+			}).
+			Compensation(func(_ context.Context, data core.SagaData) msg.DomainCommand {
+				return releaseStock{OrderID: data.(*orderData).OrderID}
+			})).
+		Step(saga.NewRemoteStep().
+			Action(func(_ context.Context, data core.SagaData) msg.DomainCommand {
+				return authorizePayment{OrderID: data.(*orderData).OrderID}
+			}).
+			Pivot()).
+		Step(saga.NewRemoteStep().
+			Action(func(_ context.Context, data core.SagaData) msg.DomainCommand {
+				return shipOrder{OrderID: data.(*orderData).OrderID}
+			}).
+			Retriable()).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	s := sagatest.NewScenario(t, definition,
+		saga.WithOrchestratorRetriableBackoff(retry.NewConstantBackoff(retry.WithBackoffInitialInterval(0))),
+	)
+
+	s.Start(&orderData{OrderID: "order-id"}).
+		ExpectCommand(reserveStock{}, "inventory").
+		ReplySuccess().
+		ExpectCommand(authorizePayment{}, "payment").
+		ReplySuccess().
+		ExpectCommand(shipOrder{}, "shipping").
+		ReplyFailure().
+		ExpectCommand(shipOrder{}, "shipping").
+		ExpectRunning()
+
+	if err = s.Orchestrator().Compensate(context.Background(), s.Instance().SagaID()); !errors.Is(err, saga.ErrSagaPastPivot) {
+		t.Errorf("Compensate() error = %v, want %v", err, saga.ErrSagaPastPivot)
+	}
+
+	s.ReplyFailure().
+		ExpectCommand(shipOrder{}, "shipping").
+		ReplySuccess().
+		ExpectCompleted().
+		ExpectNoCommands("inventory").
+		ExpectCompensationPath()
+}
+
+func TestScenarioAwait(t *testing.T) {
+	definition, err := saga.NewDefinition("sagatest.await", "order-replies").
+		Step(saga.NewRemoteStep().
+			Action(func(_ context.Context, data core.SagaData) msg.DomainCommand {
+				return reserveStock{OrderID: data.(*orderData).OrderID}
+			}).
+			Compensation(func(_ context.Context, data core.SagaData) msg.DomainCommand {
+				return releaseStock{OrderID: data.(*orderData).OrderID}
+			})).
+		Step(saga.NewAwaitStep(orderApproved{},
+			func(data core.SagaData) string { return data.(*orderData).OrderID },
+			func(event msg.Event) string { return event.Event().(*orderApproved).OrderID },
+		).Handle(func(_ context.Context, data core.SagaData, _ core.Event) error {
+			data.(*orderData).Authorized = true
+			return nil
+		})).
+		Step(saga.NewRemoteStep().
+			Action(func(_ context.Context, data core.SagaData) msg.DomainCommand {
+				return shipOrder{OrderID: data.(*orderData).OrderID}
+			}).
+			NonCompensatable()).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	sagatest.NewScenario(t, definition).
+		Start(&orderData{OrderID: "order-id"}).
+		ExpectCommand(reserveStock{}, "inventory").
+		ReplySuccess().
+		Event(orderApproved{OrderID: "other-order-id"}).
+		ExpectNoCommands("shipping").
+		ExpectRunning().
+		Event(orderApproved{OrderID: "order-id"}).
+		ExpectCommand(shipOrder{}, "shipping").
+		ExpectSagaData(func(t testing.TB, data core.SagaData) {
+			if !data.(*orderData).Authorized {
+				t.Errorf("saga data = %+v", data)
+			}
+		}).
+		ReplySuccess().
+		ExpectCompleted()
+}
+
+func TestScenarioSubSaga(t *testing.T) {
+	paymentDefinition, err := saga.NewDefinition("sagatest.payment", "payment-replies").
+		Step(saga.NewRemoteStep().
+			Action(func(_ context.Context, data core.SagaData) msg.DomainCommand {
+				return authorizePayment{OrderID: data.(*paymentData).OrderID}
+			}).
+			Compensation(func(_ context.Context, data core.SagaData) msg.DomainCommand {
+				return voidPayment{OrderID: data.(*paymentData).OrderID}
+			})).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	payment := sagatest.NewScenario(t, paymentDefinition)
+
+	definition, err := saga.NewDefinition("sagatest.fulfilment", "fulfilment-replies").
+		Step(saga.NewRemoteStep().
+			Action(func(_ context.Context, data core.SagaData) msg.DomainCommand {
+				return reserveStock{OrderID: data.(*orderData).OrderID}
+			}).
+			Compensation(func(_ context.Context, data core.SagaData) msg.DomainCommand {
+				return releaseStock{OrderID: data.(*orderData).OrderID}
+			})).
+		Step(saga.NewSubSagaStep(payment.Orchestrator(), func(_ context.Context, data core.SagaData) core.SagaData {
+			return &paymentData{OrderID: data.(*orderData).OrderID}
+		}).Handle(func(_ context.Context, data, _ core.SagaData) error {
+			data.(*orderData).Authorized = true
+			return nil
+		})).
+		Step(saga.NewRemoteStep().
+			Action(func(_ context.Context, data core.SagaData) msg.DomainCommand {
+				return shipOrder{OrderID: data.(*orderData).OrderID}
+			}).
+			NonCompensatable()).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	fulfilment := sagatest.NewScenario(t, definition).
+		Start(&orderData{OrderID: "order-id"}).
+		ExpectCommand(reserveStock{}, "inventory").
+		ReplySuccess().
+		ExpectSubSaga(payment)
+
+	payment.
+		ExpectCommand(authorizePayment{}, "payment").
+		ReplySuccess().
+		ExpectCompleted()
+
+	fulfilment.
+		ReplyFromSubSaga(payment).
+		ExpectSagaData(func(t testing.TB, data core.SagaData) {
+			if !data.(*orderData).Authorized {
+				t.Errorf("saga data = %+v", data)
+			}
+		}).
+		ExpectCommand(shipOrder{}, "shipping").
+		ReplyFailure()
+
+	payment.
+		ExpectCommand(voidPayment{}, "payment").
+		ReplySuccess().
+		ExpectCompensated().
+		ExpectHooks(saga.SagaStarting, saga.SagaCompleted, saga.SagaCompensated)
+
+	fulfilment.
+		ReplyFromSubSaga(payment).
+		ExpectCommand(releaseStock{}, "inventory").
+		ReplySuccess().
+		ExpectCompensated().
+		ExpectCompensationPath(1, 0)
+
+	if parentID := payment.Instance().ParentSagaID(); parentID != fulfilment.Instance().SagaID() {
+		t.Errorf("ParentSagaID() = %s, want %s", parentID, fulfilment.Instance().SagaID())
+	}
+}
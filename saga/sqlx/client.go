@@ -0,0 +1,122 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Client covers a subset of what both *sqlx.DB and *sqlx.Tx provide
+type Client interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error)
+	QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row
+	Rebind(query string) string
+}
+
+var _ Client = (*sqlx.DB)(nil)
+var _ Client = (*sqlx.Tx)(nil)
+
+type sessionClient struct {
+	reader *sqlx.DB
+}
+
+var _ Client = (*sessionClient)(nil)
+
+// NewSessionClient returns a Client that uses the *sqlx.Tx found in the context of each call
+func NewSessionClient(options ...SessionClientOption) Client {
+	c := &sessionClient{}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+// WithTx returns a copy of the context carrying the transaction used by the session client
+func WithTx(ctx context.Context, tx *sqlx.Tx) context.Context {
+	return context.WithValue(ctx, sqlxTxKey, tx)
+}
+
+func (c sessionClient) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	tx, err := txFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.ExecContext(ctx, tx.Rebind(query), args...)
+}
+
+func (c sessionClient) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	tx, err := txFromContext(ctx)
+	if errors.Is(err, ErrTxNotInContext) && c.reader != nil {
+		return c.reader.QueryxContext(ctx, c.reader.Rebind(query), args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.QueryxContext(ctx, tx.Rebind(query), args...)
+}
+
+func (c sessionClient) QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
+	tx, err := txFromContext(ctx)
+	if errors.Is(err, ErrTxNotInContext) && c.reader != nil {
+		return c.reader.QueryRowxContext(ctx, c.reader.Rebind(query), args...)
+	}
+	if err != nil {
+		return rowError(err)
+	}
+
+	return tx.QueryRowxContext(ctx, tx.Rebind(query), args...)
+}
+
+// Rebind is not given a context to find the driver in; queries are rebound by the transaction when they are executed
+func (c sessionClient) Rebind(query string) string {
+	return query
+}
+
+func txFromContext(ctx context.Context) (*sqlx.Tx, error) {
+	value := ctx.Value(sqlxTxKey)
+	if value == nil {
+		return nil, ErrTxNotInContext
+	}
+
+	tx, ok := value.(*sqlx.Tx)
+	if !ok {
+		return nil, ErrInvalidTxValue
+	}
+
+	return tx, nil
+}
+
+// rowError returns a row whose Scan returns err; sqlx.Row has no other way to carry an error it did not query
+func rowError(err error) *sqlx.Row {
+	db := sqlx.NewDb(sql.OpenDB(errorConnector{err}), "")
+	defer db.Close()
+
+	return db.QueryRowxContext(context.Background(), "")
+}
+
+// errorConnector fails every connection with err
+type errorConnector struct {
+	err error
+}
+
+func (c errorConnector) Connect(context.Context) (driver.Conn, error) {
+	return nil, c.err
+}
+
+func (c errorConnector) Driver() driver.Driver {
+	return errorDriver(c)
+}
+
+type errorDriver errorConnector
+
+func (d errorDriver) Open(string) (driver.Conn, error) {
+	return nil, d.err
+}
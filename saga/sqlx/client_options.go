@@ -0,0 +1,15 @@
+package sqlx
+
+import (
+	"github.com/jmoiron/sqlx"
+)
+
+// SessionClientOption options for the session client
+type SessionClientOption func(*sessionClient)
+
+// WithSessionClientReader is an option to query with the reader, e.g. the *sqlx.DB, outside of transactions
+func WithSessionClientReader(reader *sqlx.DB) SessionClientOption {
+	return func(client *sessionClient) {
+		client.reader = reader
+	}
+}
@@ -0,0 +1,102 @@
+package sqlx
+
+import (
+	"errors"
+)
+
+type contextKey int
+
+const (
+	DefaultOutboxTableName       = "outbox"
+	DefaultSagaInstanceTableName = "saga_instances"
+	DefaultSagaInstanceListLimit = 100
+
+	// CreateSagaInstancesTableMySQL creates the saga instance table; the DSN must set parseTime=true
+	CreateSagaInstancesTableMySQL = `CREATE TABLE %s (
+    saga_name          VARCHAR(255) NOT NULL,
+    saga_id            VARCHAR(255) NOT NULL,
+    saga_data_name     VARCHAR(255) NOT NULL,
+    saga_data          LONGBLOB     NOT NULL,
+    current_step       INT          NOT NULL,
+    end_state          BOOLEAN      NOT NULL,
+    compensating       BOOLEAN      NOT NULL,
+    modified_at        DATETIME(6)  NOT NULL,
+    deadline           DATETIME(6)  NULL,
+    step_deadline      DATETIME(6)  NULL,
+    version            INT          NOT NULL DEFAULT 0,
+    execution_state    BLOB         NULL,
+    correlation_key    VARCHAR(255) NULL,
+    parent_saga_name   VARCHAR(255) NULL,
+    parent_saga_id     VARCHAR(255) NULL,
+    definition_version INT          NOT NULL DEFAULT 1,
+    PRIMARY KEY (saga_name, saga_id),
+    KEY (saga_name, end_state, modified_at),
+    KEY (saga_name, correlation_key),
+    KEY (parent_saga_name, parent_saga_id)
+)`
+
+	CreateSagaInstancesTablePostgres = `CREATE TABLE %[1]s (
+    saga_name          text        NOT NULL,
+    saga_id            text        NOT NULL,
+    saga_data_name     text        NOT NULL,
+    saga_data          bytea       NOT NULL,
+    current_step       int         NOT NULL,
+    end_state          boolean     NOT NULL,
+    compensating       boolean     NOT NULL,
+    modified_at        timestamptz NOT NULL,
+    deadline           timestamptz,
+    step_deadline      timestamptz,
+    version            int         NOT NULL DEFAULT 0,
+    execution_state    bytea,
+    correlation_key    text,
+    parent_saga_name   text,
+    parent_saga_id     text,
+    definition_version int         NOT NULL DEFAULT 1,
+    PRIMARY KEY (saga_name, saga_id)
+);
+CREATE INDEX %[1]s_correlation_key_idx ON %[1]s (saga_name, correlation_key) WHERE correlation_key IS NOT NULL;
+CREATE INDEX %[1]s_parent_idx ON %[1]s (parent_saga_name, parent_saga_id) WHERE parent_saga_id IS NOT NULL`
+
+	CreateOutboxTableMySQL = `CREATE TABLE %s (
+    sequence     BIGINT       NOT NULL AUTO_INCREMENT,
+    id           VARCHAR(64)  NOT NULL,
+    channel      VARCHAR(255) NOT NULL,
+    headers      BLOB         NOT NULL,
+    payload      LONGBLOB     NOT NULL,
+    created_at   TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    published_at TIMESTAMP(6) NULL,
+    PRIMARY KEY (sequence),
+    UNIQUE KEY (id),
+    KEY (published_at, sequence)
+)`
+
+	CreateOutboxTablePostgres = `CREATE TABLE %[1]s (
+    sequence     bigserial   NOT NULL,
+    id           text        NOT NULL UNIQUE,
+    channel      text        NOT NULL,
+    headers      bytea       NOT NULL,
+    payload      bytea       NOT NULL,
+    created_at   timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_at timestamptz,
+    PRIMARY KEY (sequence)
+);
+CREATE INDEX %[1]s_unpublished_idx ON %[1]s (sequence) WHERE published_at IS NULL`
+
+	findSagaInstanceSQL           = "SELECT saga_name, saga_id, saga_data_name, saga_data, current_step, end_state, compensating, deadline, step_deadline, version, modified_at, execution_state, correlation_key, parent_saga_name, parent_saga_id, definition_version FROM %s WHERE saga_name = ? AND saga_id = ?"
+	findOverdueSagaInstancesSQL   = "SELECT saga_name, saga_id, saga_data_name, saga_data, current_step, end_state, compensating, deadline, step_deadline, version, modified_at, execution_state, correlation_key, parent_saga_name, parent_saga_id, definition_version FROM %s WHERE saga_name = ? AND end_state = false AND (step_deadline < ? OR (compensating = false AND deadline < ?)) ORDER BY modified_at LIMIT ?"
+	findCorrelatedSagaInstanceSQL = "SELECT saga_name, saga_id, saga_data_name, saga_data, current_step, end_state, compensating, deadline, step_deadline, version, modified_at, execution_state, correlation_key, parent_saga_name, parent_saga_id, definition_version FROM %s WHERE saga_name = ? AND correlation_key = ? AND end_state = false ORDER BY modified_at DESC LIMIT 1"
+	findChildSagaInstancesSQL     = "SELECT saga_name, saga_id, saga_data_name, saga_data, current_step, end_state, compensating, deadline, step_deadline, version, modified_at, execution_state, correlation_key, parent_saga_name, parent_saga_id, definition_version FROM %s WHERE parent_saga_name = ? AND parent_saga_id = ? ORDER BY modified_at"
+	listSagaInstancesSQL          = "SELECT saga_name, saga_id, saga_data_name, saga_data, current_step, end_state, compensating, deadline, step_deadline, version, modified_at, execution_state, correlation_key, parent_saga_name, parent_saga_id, definition_version FROM %s"
+	saveSagaInstanceSQL           = "INSERT INTO %s (saga_name, saga_id, saga_data_name, saga_data, current_step, end_state, compensating, deadline, step_deadline, version, execution_state, correlation_key, parent_saga_name, parent_saga_id, definition_version, modified_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	updateSagaInstanceSQL         = "UPDATE %s SET saga_data_name = ?, saga_data = ?, current_step = ?, end_state = ?, compensating = ?, deadline = ?, step_deadline = ?, execution_state = ?, correlation_key = ?, version = version + 1, modified_at = ? WHERE saga_name = ? AND saga_id = ? AND version = ?"
+
+	saveOutboxMessageSQL           = "INSERT INTO %s (id, channel, headers, payload, created_at) VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)"
+	lockOutboxChannelsSQL          = "SELECT channel FROM %[1]s WHERE published_at IS NULL AND sequence IN (SELECT MIN(sequence) FROM %[1]s WHERE published_at IS NULL GROUP BY channel) ORDER BY sequence LIMIT ? FOR UPDATE SKIP LOCKED"
+	fetchOutboxMessagesSQL         = "SELECT id, channel, headers, payload FROM %s WHERE published_at IS NULL AND channel IN (?) ORDER BY sequence LIMIT ?"
+	markOutboxMessagesPublishedSQL = "UPDATE %s SET published_at = CURRENT_TIMESTAMP WHERE id IN (?)"
+
+	sqlxTxKey = contextKey(3306)
+)
+
+var ErrTxNotInContext = errors.New("sqlx.Tx is not set for session")
+var ErrInvalidTxValue = errors.New("tx value is not a *sqlx.Tx type")
@@ -0,0 +1,17 @@
+package sqlx
+
+import "github.com/nguyenta1993/service-kit/logger"
+
+type OutboxProducerOption func(*OutboxProducer)
+
+func WithOutboxProducerTableName(tableName string) OutboxProducerOption {
+	return func(producer *OutboxProducer) {
+		producer.tableName = tableName
+	}
+}
+
+func WithOutboxProducerLogger(logger logger.Logger) OutboxProducerOption {
+	return func(producer *OutboxProducer) {
+		producer.logger = logger
+	}
+}
@@ -0,0 +1,182 @@
+package sqlx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+	"go.uber.org/zap"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	DefaultOutboxRelayBatchSize    = 100
+	DefaultOutboxRelayPollInterval = 500 * time.Millisecond
+)
+
+// OutboxRelay delivers messages saved by an OutboxProducer in order per channel, skipping channels locked by other relays
+type OutboxRelay struct {
+	tableName    string
+	db           *sqlx.DB
+	producer     msg.Producer
+	logger       logger.Logger
+	batchSize    int
+	pollInterval time.Duration
+}
+
+type outboxMessage struct {
+	channel string
+	message msg.Message
+}
+
+// NewOutboxRelay constructs a new OutboxRelay
+func NewOutboxRelay(logger logger.Logger, db *sqlx.DB, producer msg.Producer, options ...OutboxRelayOption) *OutboxRelay {
+	r := &OutboxRelay{
+		tableName:    DefaultOutboxTableName,
+		db:           db,
+		producer:     producer,
+		logger:       logger,
+		batchSize:    DefaultOutboxRelayBatchSize,
+		pollInterval: DefaultOutboxRelayPollInterval,
+	}
+
+	for _, option := range options {
+		option(r)
+	}
+
+	return r
+}
+
+// Start delivers outbox messages until the context is cancelled
+func (r *OutboxRelay) Start(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+		}
+
+		delivered, err := r.relay(ctx)
+		if err != nil {
+			r.logger.Error("error relaying outbox messages", zap.Error(err))
+		}
+
+		// keep draining without waiting while full batches are being delivered
+		if err == nil && delivered == r.batchSize {
+			timer.Reset(0)
+		} else {
+			timer.Reset(r.pollInterval)
+		}
+	}
+}
+
+func (r *OutboxRelay) relay(ctx context.Context) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		// rolling back a committed transaction only returns sql.ErrTxDone
+		_ = tx.Rollback()
+	}()
+
+	channels, err := r.lockChannels(ctx, tx)
+	if err != nil || len(channels) == 0 {
+		return 0, err
+	}
+
+	messages, err := r.fetch(ctx, tx, channels)
+	if err != nil {
+		return 0, err
+	}
+
+	blocked := make(map[string]struct{})
+	delivered := make([]string, 0, len(messages))
+
+	for _, m := range messages {
+		if _, exists := blocked[m.channel]; exists {
+			continue
+		}
+
+		if err = r.producer.Send(ctx, m.channel, m.message); err != nil {
+			r.logger.Error("error delivering outbox message",
+				zap.String("MessageID", m.message.ID()),
+				zap.String("Destination", m.channel),
+				zap.Error(err),
+			)
+			blocked[m.channel] = struct{}{}
+			continue
+		}
+
+		delivered = append(delivered, m.message.ID())
+	}
+
+	if len(delivered) > 0 {
+		query, args, err := sqlx.In(fmt.Sprintf(markOutboxMessagesPublishedSQL, r.tableName), delivered)
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err = tx.ExecContext(ctx, tx.Rebind(query), args...); err != nil {
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return len(delivered), nil
+}
+
+// lockChannels locks the oldest undelivered message of channels no other relay has locked and returns the channels
+func (r *OutboxRelay) lockChannels(ctx context.Context, tx *sqlx.Tx) ([]string, error) {
+	var channels []string
+
+	err := tx.SelectContext(ctx, &channels, tx.Rebind(fmt.Sprintf(lockOutboxChannelsSQL, r.tableName)), r.batchSize)
+
+	return channels, err
+}
+
+func (r *OutboxRelay) fetch(ctx context.Context, tx *sqlx.Tx, channels []string) ([]outboxMessage, error) {
+	query, args, err := sqlx.In(fmt.Sprintf(fetchOutboxMessagesSQL, r.tableName), channels, r.batchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, tx.Rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []outboxMessage
+
+	for rows.Next() {
+		var id, channel string
+		var headerData, payload []byte
+
+		if err = rows.Scan(&id, &channel, &headerData, &payload); err != nil {
+			return nil, err
+		}
+
+		var headers msg.Headers
+		if err = json.Unmarshal(headerData, &headers); err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, outboxMessage{
+			channel: channel,
+			message: msg.NewMessage(payload, msg.WithMessageID(id), msg.WithHeaders(headers)),
+		})
+	}
+
+	return messages, rows.Err()
+}
@@ -0,0 +1,57 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/msg"
+	"go.uber.org/zap"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ReceiverSessionMiddleware runs each received message in a READ COMMITTED transaction used by the session client
+func ReceiverSessionMiddleware(db *sqlx.DB, logger logger.Logger) func(msg.MessageReceiver) msg.MessageReceiver {
+	return func(next msg.MessageReceiver) msg.MessageReceiver {
+		return msg.ReceiveMessageFunc(func(ctx context.Context, message msg.Message) (err error) {
+			var tx *sqlx.Tx
+
+			tx, err = db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+			if err != nil {
+				logger.Error("error while starting the request transaction", zap.Error(err))
+				return fmt.Errorf("failed to start transaction: %s", err.Error())
+			}
+
+			txCtx := WithTx(ctx, tx)
+
+			defer func() {
+				p := recover()
+				switch {
+				case p != nil:
+					txErr := tx.Rollback()
+					if txErr != nil {
+						logger.Error("error while rolling back the message receiver transaction during panic", zap.Error(txErr))
+					}
+					panic(p)
+				case err != nil:
+					txErr := tx.Rollback()
+					if txErr != nil {
+						logger.Error("error while rolling back the message receiver transaction", zap.Error(txErr))
+					}
+				default:
+					// the message must be received again when the instance update and the outbox were not committed
+					err = tx.Commit()
+					if err != nil {
+						logger.Error("error while committing the message receiver transaction", zap.Error(err))
+					}
+				}
+			}()
+
+			err = next.ReceiveMessage(txCtx, message)
+
+			return err
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/core"
+	"github.com/nguyenta1993/service-kit/saga/saga"
+)
+
+// SagaInstanceStore stores saga instances with MySQL or Postgres
+type SagaInstanceStore struct {
+	tableName string
+	client    Client
+	logger    logger.Logger
+}
+
+var _ saga.InstanceStore = (*SagaInstanceStore)(nil)
+var _ saga.OverdueFinder = (*SagaInstanceStore)(nil)
+var _ saga.InstanceLister = (*SagaInstanceStore)(nil)
+var _ saga.CorrelationKeyFinder = (*SagaInstanceStore)(nil)
+var _ saga.ChildFinder = (*SagaInstanceStore)(nil)
+
+func NewSagaInstanceStore(logger logger.Logger, client Client, options ...SagaInstanceStoreOption) *SagaInstanceStore {
+	s := &SagaInstanceStore{
+		tableName: DefaultSagaInstanceTableName,
+		client:    client,
+		logger:    logger,
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+func (s *SagaInstanceStore) Find(ctx context.Context, sagaName, sagaID string) (*saga.Instance, error) {
+	row := s.client.QueryRowxContext(ctx, s.client.Rebind(fmt.Sprintf(findSagaInstanceSQL, s.tableName)), sagaName, sagaID)
+
+	instance, err := s.scan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, saga.ErrInstanceNotFound
+	}
+
+	return instance, err
+}
+
+func (s *SagaInstanceStore) FindByCorrelationKey(ctx context.Context, sagaName, correlationKey string) (*saga.Instance, error) {
+	row := s.client.QueryRowxContext(ctx, s.client.Rebind(fmt.Sprintf(findCorrelatedSagaInstanceSQL, s.tableName)), sagaName, correlationKey)
+
+	instance, err := s.scan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, saga.ErrInstanceNotFound
+	}
+
+	return instance, err
+}
+
+func (s *SagaInstanceStore) FindChildren(ctx context.Context, parentSagaName, parentSagaID string) ([]*saga.Instance, error) {
+	return s.query(ctx, fmt.Sprintf(findChildSagaInstancesSQL, s.tableName), parentSagaName, parentSagaID)
+}
+
+func (s *SagaInstanceStore) FindOverdue(ctx context.Context, sagaName string, now time.Time, limit int) ([]*saga.Instance, error) {
+	return s.query(ctx, fmt.Sprintf(findOverdueSagaInstancesSQL, s.tableName), sagaName, now, now, limit)
+}
+
+func (s *SagaInstanceStore) List(ctx context.Context, query saga.InstanceQuery) ([]*saga.Instance, error) {
+	var conditions []string
+	var args []interface{}
+
+	if query.SagaName != "" {
+		args = append(args, query.SagaName)
+		conditions = append(conditions, "saga_name = ?")
+	}
+
+	switch query.State {
+	case saga.InstanceRunning:
+		conditions = append(conditions, "end_state = false AND compensating = false")
+	case saga.InstanceCompensating:
+		conditions = append(conditions, "end_state = false AND compensating = true")
+	case saga.InstanceEnded:
+		conditions = append(conditions, "end_state = true")
+	}
+
+	if !query.ModifiedBefore.IsZero() {
+		args = append(args, query.ModifiedBefore)
+		conditions = append(conditions, "modified_at < ?")
+	}
+
+	if query.DefinitionVersion != 0 {
+		args = append(args, query.DefinitionVersion)
+		conditions = append(conditions, "definition_version = ?")
+	}
+
+	sql := fmt.Sprintf(listSagaInstancesSQL, s.tableName)
+	if len(conditions) > 0 {
+		sql += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultSagaInstanceListLimit
+	}
+	args = append(args, limit, query.Offset)
+	sql += " ORDER BY modified_at DESC LIMIT ? OFFSET ?"
+
+	return s.query(ctx, sql, args...)
+}
+
+func (s *SagaInstanceStore) Save(ctx context.Context, sagaInstance *saga.Instance) error {
+	data, err := core.SerializeSagaData(sagaInstance.SagaData())
+	if err != nil {
+		return err
+	}
+	state, err := json.Marshal(sagaInstance.ExecutionState())
+	if err != nil {
+		return err
+	}
+	_, err = s.client.ExecContext(ctx, s.client.Rebind(fmt.Sprintf(saveSagaInstanceSQL, s.tableName)), sagaInstance.SagaName(), sagaInstance.SagaID(), core.VersionedSagaDataName(sagaInstance.SagaData()), data, sagaInstance.CurrentStep(), sagaInstance.EndState(), sagaInstance.Compensating(), nullTime(sagaInstance.Deadline()), nullTime(sagaInstance.StepDeadline()), sagaInstance.Version(), state, nullString(sagaInstance.CorrelationKey()), nullString(sagaInstance.ParentSagaName()), nullString(sagaInstance.ParentSagaID()), sagaInstance.DefinitionVersion(), time.Now().UTC())
+	return err
+}
+
+func (s *SagaInstanceStore) Update(ctx context.Context, sagaInstance *saga.Instance) error {
+	data, err := core.SerializeSagaData(sagaInstance.SagaData())
+	if err != nil {
+		return err
+	}
+	state, err := json.Marshal(sagaInstance.ExecutionState())
+	if err != nil {
+		return err
+	}
+	result, err := s.client.ExecContext(ctx, s.client.Rebind(fmt.Sprintf(updateSagaInstanceSQL, s.tableName)), core.VersionedSagaDataName(sagaInstance.SagaData()), data, sagaInstance.CurrentStep(), sagaInstance.EndState(), sagaInstance.Compensating(), nullTime(sagaInstance.Deadline()), nullTime(sagaInstance.StepDeadline()), state, nullString(sagaInstance.CorrelationKey()), time.Now().UTC(), sagaInstance.SagaName(), sagaInstance.SagaID(), sagaInstance.Version())
+	if err != nil {
+		return err
+	}
+
+	// MySQL reports changed rows rather than matched rows; the version always changes so the two are the same
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return &saga.ErrInstanceConflict{
+			SagaName: sagaInstance.SagaName(),
+			SagaID:   sagaInstance.SagaID(),
+			Version:  sagaInstance.Version(),
+		}
+	}
+
+	return nil
+}
+
+func (s *SagaInstanceStore) query(ctx context.Context, query string, args ...interface{}) ([]*saga.Instance, error) {
+	rows, err := s.client.QueryxContext(ctx, s.client.Rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var instances []*saga.Instance
+
+	for rows.Next() {
+		instance, err := s.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, instance)
+	}
+
+	return instances, rows.Err()
+}
+
+func (s *SagaInstanceStore) scan(row interface{ Scan(...interface{}) error }) (*saga.Instance, error) {
+	var sagaName, sagaID, dataName string
+	var data, state []byte
+	var currentStep int
+	var endState, compensating bool
+	var deadline, stepDeadline sql.NullTime
+	var version, definitionVersion int
+	var modifiedAt time.Time
+	var correlationKey, parentSagaName, parentSagaID sql.NullString
+
+	err := row.Scan(&sagaName, &sagaID, &dataName, &data, &currentStep, &endState, &compensating, &deadline, &stepDeadline, &version, &modifiedAt, &state, &correlationKey, &parentSagaName, &parentSagaID, &definitionVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	var executionState saga.ExecutionState
+	if len(state) > 0 {
+		if err = json.Unmarshal(state, &executionState); err != nil {
+			return nil, err
+		}
+	}
+
+	sagaData, err := core.DeserializeSagaData(dataName, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return saga.NewSagaInstance(sagaName, sagaID, sagaData, currentStep, endState, compensating,
+		saga.WithInstanceDeadline(deadline.Time),
+		saga.WithInstanceStepDeadline(stepDeadline.Time),
+		saga.WithInstanceVersion(version),
+		saga.WithInstanceModifiedAt(modifiedAt),
+		saga.WithInstanceExecutionState(executionState),
+		saga.WithInstanceCorrelationKey(correlationKey.String),
+		saga.WithInstanceParent(parentSagaName.String, parentSagaID.String),
+		saga.WithInstanceDefinitionVersion(definitionVersion),
+	), nil
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t.UTC(), Valid: !t.IsZero()}
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
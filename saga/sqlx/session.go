@@ -0,0 +1,82 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"github.com/nguyenta1993/service-kit/logger"
+	"github.com/nguyenta1993/service-kit/saga/saga"
+)
+
+// savepoints numbers the savepoints of the sessions so nested sessions do not release each other
+var savepoints atomic.Int64
+
+// NewSession returns a saga.Session that runs in a READ COMMITTED transaction, or a savepoint of the transaction in the context
+func NewSession(db *sqlx.DB, logger logger.Logger) saga.Session {
+	return func(ctx context.Context, fn func(context.Context) error) (err error) {
+		if outer, txErr := txFromContext(ctx); txErr == nil {
+			return savepoint(ctx, outer, logger, fn)
+		}
+
+		var tx *sqlx.Tx
+
+		tx, err = db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+		if err != nil {
+			logger.Error("error while starting the saga session transaction", zap.Error(err))
+			return err
+		}
+
+		defer func() {
+			p := recover()
+			switch {
+			case p != nil:
+				if txErr := tx.Rollback(); txErr != nil {
+					logger.Error("error while rolling back the saga session transaction during panic", zap.Error(txErr))
+				}
+				panic(p)
+			case err != nil:
+				if txErr := tx.Rollback(); txErr != nil {
+					logger.Error("error while rolling back the saga session transaction", zap.Error(txErr))
+				}
+			default:
+				err = tx.Commit()
+			}
+		}()
+
+		return fn(WithTx(ctx, tx))
+	}
+}
+
+// savepoint runs fn in a savepoint of the transaction, which is rolled back to when fn fails
+func savepoint(ctx context.Context, tx *sqlx.Tx, logger logger.Logger, fn func(context.Context) error) (err error) {
+	name := fmt.Sprintf("saga_session_%d", savepoints.Add(1))
+
+	if _, err = tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		logger.Error("error while starting the saga session savepoint", zap.Error(err))
+		return err
+	}
+
+	defer func() {
+		p := recover()
+		switch {
+		case p != nil:
+			if _, txErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); txErr != nil {
+				logger.Error("error while rolling back the saga session savepoint during panic", zap.Error(txErr))
+			}
+			panic(p)
+		case err != nil:
+			if _, txErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); txErr != nil {
+				logger.Error("error while rolling back the saga session savepoint", zap.Error(txErr))
+			}
+		default:
+			_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+		}
+	}()
+
+	return fn(ctx)
+}